@@ -3,6 +3,7 @@ package constants
 const (
 	FILTER_TYPE_SIMPLE       = "SIMPLE"
 	FILTER_TYPE_COLUMN       = "COLUMN"
+	FILTER_TYPE_SUB          = "SUB"
 	FILTER_TYPE_BETWEEN      = "BETWEEN"
 	FILTER_TYPE_NOT_BETWEEN  = "NOT_BETWEEN"
 	FILTER_TYPE_IN           = "IN"
@@ -13,6 +14,8 @@ const (
 	FILTER_TYPE_NUMERIC      = "NUMERIC"
 	FILTER_TYPE_DATE_BETWEEN = "DATE_BETWEEN"
 	FILTER_TYPE_TERM         = "TERM"
+	FILTER_TYPE_GROUP        = "GROUP"
+	FILTER_TYPE_NOT_GROUP    = "NOT_GROUP"
 
 	FILTER_CONNECTOR_AND = "AND"
 	FILTER_CONNECTOR_OR  = "OR"
@@ -26,3 +29,16 @@ const (
 	FILTER_OPERATOR_LIKE        = "LIKE"
 	FILTER_OPERATOR_ILIKE       = "ILIKE"
 )
+
+const (
+	// Cantidad máxima de valores en un filtro IN/NOT_IN cuando ListParams.MaxInValues es 0
+	DEFAULT_MAX_IN_VALUES = 1000
+	// A partir de esta cantidad de valores se enlaza la lista como un arreglo si el dialecto lo soporta
+	IN_ARRAY_THRESHOLD = 20
+	// Cantidad máxima de filas por página del adaptador OData, el resto se entrega con @odata.nextLink
+	ODATA_MAX_PAGE_SIZE = 100
+	// Cantidad máxima de filas del adaptador DataTables, se usa también cuando length es -1
+	DATATABLES_MAX_LENGTH = 1000
+	// Cantidad máxima de filas de la ruta "/" del handler HTTP, se usa también cuando no se indica limit
+	HANDLER_MAX_LIMIT = 1000
+)
@@ -0,0 +1,384 @@
+package datatables
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/devsstudio/gosql/constants"
+	httphandler "github.com/devsstudio/gosql/handler"
+	"github.com/devsstudio/gosql/helpers"
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/response"
+	"github.com/devsstudio/gosql/services"
+	"github.com/devsstudio/gosql/types"
+)
+
+// Search es un valor de búsqueda, global o de una columna
+type Search struct {
+	Value string
+	Regex bool
+}
+
+// Column es una columna de la tabla; Data es el alias de la columna en el servicio
+type Column struct {
+	Data       string
+	Name       string
+	Searchable bool
+	Orderable  bool
+	Search     Search
+}
+
+// Order es una clave de ordenamiento, Column es el índice en Columns
+type Order struct {
+	Column int
+	Dir    string
+}
+
+// Request son los parámetros del procesamiento del lado del servidor de DataTables
+type Request struct {
+	Draw    int
+	Start   int
+	Length  int // -1 pide todas las filas
+	Search  Search
+	Columns []Column
+	Order   []Order
+}
+
+// Options configura el adaptador
+type Options struct {
+	MaxLength  int                              // Filas máximas por solicitud, 0 usa constants.DATATABLES_MAX_LENGTH
+	Exclusions []string                         // Columnas que nunca se devuelven
+	OnError    func(r *http.Request, err error) // Recibe los errores de la base de datos antes de responder el error genérico que muestra la tabla
+}
+
+// ErrNoSearchableColumns indica que hay búsqueda global pero ninguna columna donde buscar,
+// por lo que ninguna fila puede coincidir. Find la responde con un resultado vacío.
+var ErrNoSearchableColumns = errors.New("no searchable columns")
+
+var (
+	columnKeyRegexp = regexp.MustCompile(`^columns\[(\d+)\]\[(\w+)\](?:\[(\w+)\])?$`)
+	orderKeyRegexp  = regexp.MustCompile(`^order\[(\d+)\]\[(\w+)\]$`)
+)
+
+// ParseRequest interpreta los parámetros que envía DataTables (draw, start, length, search[value],
+// columns[i][data], columns[i][search][value], order[i][column], order[i][dir], ...)
+func ParseRequest(values url.Values) (*Request, error) {
+	dataTablesRequest := &Request{Length: 10}
+
+	var err error
+	if dataTablesRequest.Draw, err = parseInt(values, "draw", 0); err != nil {
+		return nil, err
+	}
+	if dataTablesRequest.Start, err = parseInt(values, "start", 0); err != nil {
+		return nil, err
+	}
+	if dataTablesRequest.Length, err = parseInt(values, "length", 10); err != nil {
+		return nil, err
+	}
+	if dataTablesRequest.Start < 0 || dataTablesRequest.Length < -1 {
+		return nil, errors.New("start and length cannot be negative")
+	}
+	dataTablesRequest.Search = Search{Value: values.Get("search[value]"), Regex: values.Get("search[regex]") == "true"}
+
+	columns := map[int]*Column{}
+	orders := map[int]*Order{}
+	for key, items := range values {
+		value := items[0]
+		if match := columnKeyRegexp.FindStringSubmatch(key); match != nil {
+			index, _ := strconv.Atoi(match[1])
+			column, exists := columns[index]
+			if !exists {
+				column = &Column{Searchable: true, Orderable: true}
+				columns[index] = column
+			}
+			switch match[2] + "." + match[3] {
+			case "data.":
+				column.Data = value
+			case "name.":
+				column.Name = value
+			case "searchable.":
+				column.Searchable = value == "true"
+			case "orderable.":
+				column.Orderable = value == "true"
+			case "search.value":
+				column.Search.Value = value
+			case "search.regex":
+				column.Search.Regex = value == "true"
+			}
+		} else if match := orderKeyRegexp.FindStringSubmatch(key); match != nil {
+			index, _ := strconv.Atoi(match[1])
+			order, exists := orders[index]
+			if !exists {
+				order = &Order{Column: -1}
+				orders[index] = order
+			}
+			switch match[2] {
+			case "column":
+				column, err := strconv.Atoi(value)
+				if err != nil {
+					return nil, errors.New("order[" + match[1] + "][column] must be an integer")
+				}
+				order.Column = column
+			case "dir":
+				order.Dir = value
+			}
+		}
+	}
+
+	for index := 0; index < len(columns); index++ {
+		column, exists := columns[index]
+		if !exists {
+			return nil, errors.New("columns must be numbered consecutively from 0")
+		}
+		dataTablesRequest.Columns = append(dataTablesRequest.Columns, *column)
+	}
+
+	indexes := make([]int, 0, len(orders))
+	for index := range orders {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	for _, index := range indexes {
+		order := orders[index]
+		if order.Column < 0 || order.Column >= len(dataTablesRequest.Columns) {
+			return nil, errors.New("order[" + strconv.Itoa(index) + "][column] does not exist")
+		}
+		dataTablesRequest.Order = append(dataTablesRequest.Order, *order)
+	}
+
+	return dataTablesRequest, nil
+}
+
+func parseInt(values url.Values, key string, defaultValue int) (int, error) {
+	value := values.Get(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, errors.New(key + " must be an integer")
+	}
+	return number, nil
+}
+
+// attribute devuelve el alias de la columna en el servicio, data o en su defecto name
+func (column Column) attribute() string {
+	if column.Data != "" {
+		return column.Data
+	}
+	return column.Name
+}
+
+// Filters arma los filtros de la solicitud. La búsqueda global se separa en palabras y cada
+// palabra es un filtro TERM con ILIKE sobre las columnas buscables que lo admiten; la búsqueda
+// de una columna usa ILIKE o, si la columna no lo admite (p.e. numérica), igualdad, y se ignora
+// en las columnas no buscables o que el servicio no permite filtrar. El texto buscado se compara
+// literalmente. Si hay búsqueda global pero ninguna columna buscable devuelve ErrNoSearchableColumns.
+func (dataTablesRequest *Request) Filters(service *services.Pagination) ([]request.FilterRequest, error) {
+	filters := []request.FilterRequest{}
+
+	if words := strings.Fields(dataTablesRequest.Search.Value); len(words) > 0 {
+		if dataTablesRequest.Search.Regex {
+			return nil, errors.New("regex search is not supported")
+		}
+
+		var attrs []string
+		for _, column := range dataTablesRequest.Columns {
+			if column.Searchable && service.SupportsFilter(column.attribute(), "TERM", "ILIKE") {
+				attrs = append(attrs, column.attribute())
+			}
+		}
+		if len(attrs) == 0 {
+			return nil, ErrNoSearchableColumns
+		}
+		for _, word := range words {
+			filters = append(filters, request.FilterRequest{Type: "TERM", Attrs: attrs, Opr: "ILIKE", Val: "%" + request.EscapeLike(word) + "%", Conn: "AND"})
+		}
+	}
+
+	for _, column := range dataTablesRequest.Columns {
+		value := strings.TrimSpace(column.Search.Value)
+		if value == "" || !column.Searchable {
+			continue
+		}
+		if column.Search.Regex {
+			return nil, errors.New("regex search is not supported")
+		}
+
+		filter := request.FilterRequest{Type: "SIMPLE", Attr: column.attribute(), Opr: "ILIKE", Val: "%" + request.EscapeLike(value) + "%", Conn: "AND"}
+		if !service.SupportsFilter(filter.Attr, "SIMPLE", "ILIKE") {
+			if !service.SupportsFilter(filter.Attr, "SIMPLE", "=") {
+				continue
+			}
+			filter.Opr = "="
+			filter.Val = value
+		}
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+// OrderBy devuelve las claves de ordenamiento de la solicitud
+func (dataTablesRequest *Request) OrderBy() (types.Order, error) {
+	var order types.Order
+	for _, item := range dataTablesRequest.Order {
+		column := dataTablesRequest.Columns[item.Column]
+		if !column.Orderable {
+			return nil, errors.New("attribute order '" + column.attribute() + "' is not allowed")
+		}
+		direction := item.Dir
+		if direction == "" {
+			direction = "asc"
+		}
+		order = append(order, types.OrderBy{Column: column.attribute(), Direction: direction})
+	}
+	return order, nil
+}
+
+// Find ejecuta la solicitud y devuelve solo las columnas pedidas.
+// Las columnas excluidas no participan de la búsqueda global y buscar en una de ellas
+// es un error.
+func Find(service *services.Pagination, dataTablesRequest *Request, options Options) (*response.DataTablesResponse, error) {
+	order, err := dataTablesRequest.OrderBy()
+	if err != nil {
+		return nil, err
+	}
+	searchRequest, err := withoutExcluded(dataTablesRequest, options.Exclusions)
+	if err != nil {
+		return nil, err
+	}
+	filters, err := searchRequest.Filters(service)
+	noMatches := errors.Is(err, ErrNoSearchableColumns)
+	if err != nil && !noMatches {
+		return nil, err
+	}
+
+	maxLength := options.MaxLength
+	if maxLength <= 0 {
+		maxLength = constants.DATATABLES_MAX_LENGTH
+	}
+	limit := dataTablesRequest.Length
+	if limit < 0 || limit > maxLength {
+		limit = maxLength
+	}
+
+	include, exclusions := getSelection(service, dataTablesRequest.Columns, options.Exclusions)
+	result := &response.DataTablesResponse{Draw: dataTablesRequest.Draw, Data: []map[string]interface{}{}}
+
+	// El total sin filtros se cuenta una sola vez, sin búsqueda es también el total filtrado
+	if result.RecordsTotal, err = service.Count([]request.FilterRequest{}); err != nil {
+		return nil, err
+	}
+	// Ninguna fila coincide con la búsqueda
+	if noMatches {
+		return result, nil
+	}
+	result.RecordsFiltered = result.RecordsTotal
+	if len(filters) > 0 {
+		if result.RecordsFiltered, err = service.Count(filters); err != nil {
+			return nil, err
+		}
+	}
+
+	// Con length 0 solo interesan los totales
+	if limit > 0 {
+		findRequest := request.FindRequest{Offset: dataTablesRequest.Start, Limit: limit, Order: order, Include: include}
+		if result.Data, err = service.FindAll(filters, findRequest, &exclusions); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// withoutExcluded devuelve una copia de la solicitud donde las columnas excluidas no son
+// buscables. Si alguna de ellas trae búsqueda por columna devuelve un error.
+func withoutExcluded(dataTablesRequest *Request, exclusions []string) (*Request, error) {
+	searchRequest := *dataTablesRequest
+	searchRequest.Columns = make([]Column, len(dataTablesRequest.Columns))
+	for i, column := range dataTablesRequest.Columns {
+		if helpers.ArrayContains(exclusions, column.attribute()) {
+			if strings.TrimSpace(column.Search.Value) != "" {
+				return nil, errors.New("attribute search '" + column.attribute() + "' is not allowed")
+			}
+			column.Searchable = false
+		}
+		searchRequest.Columns[i] = column
+	}
+	return &searchRequest, nil
+}
+
+// getSelection devuelve las columnas pedidas que existen en el servicio y excluye las demás.
+// Las columnas de la tabla sin data (p.e. botones de acciones) se ignoran.
+func getSelection(service *services.Pagination, columns []Column, exclusions []string) ([]string, []string) {
+	exclusions = append([]string{}, exclusions...)
+
+	requested := map[string]struct{}{}
+	var include []string
+	for _, column := range columns {
+		if def, exists := service.ColumnDef(column.attribute()); exists && def.Selectable {
+			requested[def.Name] = struct{}{}
+			include = append(include, def.Name)
+		}
+	}
+	if len(requested) == 0 {
+		return include, exclusions
+	}
+
+	for _, def := range service.ColumnDefs() {
+		if _, exists := requested[def.Name]; !exists {
+			exclusions = append(exclusions, def.Name)
+		}
+	}
+	return include, exclusions
+}
+
+type dataTablesHandler struct {
+	service *services.Pagination
+	options Options
+}
+
+// New crea un http.Handler para la opción ajax de DataTables, acepta GET y POST con formulario.
+// Las solicitudes inválidas responden 400 y los errores de la base de datos 500, ambos con el
+// mensaje en "error" para que DataTables lo muestre.
+func New(service *services.Pagination, options Options) http.Handler {
+	return &dataTablesHandler{service: service, options: options}
+}
+
+func (handler *dataTablesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		httphandler.WriteJSON(w, http.StatusMethodNotAllowed, response.DataTablesResponse{Data: []map[string]interface{}{}, Error: "method not allowed"})
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		httphandler.WriteJSON(w, http.StatusBadRequest, response.DataTablesResponse{Data: []map[string]interface{}{}, Error: "invalid form"})
+		return
+	}
+
+	dataTablesRequest, err := ParseRequest(r.Form)
+	if err != nil {
+		httphandler.WriteJSON(w, http.StatusBadRequest, response.DataTablesResponse{Data: []map[string]interface{}{}, Error: err.Error()})
+		return
+	}
+
+	result, err := Find(handler.service.WithContext(r.Context()), dataTablesRequest, handler.options)
+	if err != nil {
+		errorResponse := response.DataTablesResponse{Draw: dataTablesRequest.Draw, Data: []map[string]interface{}{}, Error: err.Error()}
+
+		status := httphandler.ErrorStatus(r, err, handler.options.OnError)
+		if status == http.StatusInternalServerError {
+			errorResponse.Error = "internal server error"
+		}
+		httphandler.WriteJSON(w, status, errorResponse)
+		return
+	}
+
+	httphandler.WriteJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,254 @@
+package datatables_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/devsstudio/gosql/datatables"
+	"github.com/devsstudio/gosql/internal/testdb"
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/services"
+	"github.com/devsstudio/gosql/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newService(t *testing.T, table string) *services.Pagination {
+	statements := []string{
+		`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, email TEXT, score INTEGER)`,
+		`INSERT INTO users VALUES (1, 'John Smith', 'john@mail.com', 10), (2, 'Jane Doe', 'jane@mail.com', 20), (3, 'Bob Smith', 'bob@test.com', 30)`,
+	}
+
+	return testdb.NewService(t, statements, types.ListParams{
+		Table: table,
+		ColumnDefs: []types.ColumnDef{
+			types.NewColumnDef("id", "id", types.ColumnTypeInt),
+			types.NewColumnDef("name", "name", types.ColumnTypeText),
+			types.NewColumnDef("email", "email", types.ColumnTypeText),
+			types.NewColumnDef("score", "score", types.ColumnTypeInt),
+		},
+	})
+}
+
+// dataTablesValues arma los parámetros que envía DataTables para las columnas indicadas
+func dataTablesValues(columns []string, overrides map[string]string) url.Values {
+	values := url.Values{
+		"draw":          {"3"},
+		"start":         {"0"},
+		"length":        {"10"},
+		"search[value]": {""},
+		"search[regex]": {"false"},
+	}
+	for i, column := range columns {
+		prefix := "columns[" + strconv.Itoa(i) + "]"
+		values.Set(prefix+"[data]", column)
+		values.Set(prefix+"[name]", "")
+		values.Set(prefix+"[searchable]", "true")
+		values.Set(prefix+"[orderable]", "true")
+		values.Set(prefix+"[search][value]", "")
+		values.Set(prefix+"[search][regex]", "false")
+	}
+	for key, value := range overrides {
+		values.Set(key, value)
+	}
+	return values
+}
+
+func TestParseRequest(t *testing.T) {
+	values := dataTablesValues([]string{"id", "name"}, map[string]string{
+		"columns[1][search][value]": "jo",
+		"columns[0][orderable]":     "false",
+		"order[0][column]":          "1",
+		"order[0][dir]":             "desc",
+		"search[value]":             "smith",
+	})
+
+	dataTablesRequest, err := datatables.ParseRequest(values)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &datatables.Request{
+		Draw:   3,
+		Start:  0,
+		Length: 10,
+		Search: datatables.Search{Value: "smith"},
+		Columns: []datatables.Column{
+			{Data: "id", Searchable: true, Orderable: false},
+			{Data: "name", Searchable: true, Orderable: true, Search: datatables.Search{Value: "jo"}},
+		},
+		Order: []datatables.Order{{Column: 1, Dir: "desc"}},
+	}, dataTablesRequest)
+}
+
+func TestParseRequest_Errors(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{"draw=x", "draw must be an integer"},
+		{"start=-5", "start and length cannot be negative"},
+		{"columns[1][data]=id", "columns must be numbered consecutively from 0"},
+		{"columns[0][data]=id&order[0][column]=4", "order[0][column] does not exist"},
+		{"columns[0][data]=id&order[0][column]=a", "order[0][column] must be an integer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+
+			dataTablesRequest, err := datatables.ParseRequest(values)
+
+			assert.Nil(t, dataTablesRequest)
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func TestRequest_Filters(t *testing.T) {
+	service := newService(t, "users")
+	dataTablesRequest := &datatables.Request{
+		Search: datatables.Search{Value: " john  smith "},
+		Columns: []datatables.Column{
+			{Data: "id", Searchable: true, Search: datatables.Search{Value: "1"}},
+			{Data: "name", Searchable: true, Search: datatables.Search{Value: "jo"}},
+			{Data: "email", Searchable: false},
+			{Data: "", Searchable: true},
+		},
+	}
+
+	filters, err := dataTablesRequest.Filters(service)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []request.FilterRequest{
+		{Type: "TERM", Attrs: []string{"name"}, Opr: "ILIKE", Val: "%john%", Conn: "AND"},
+		{Type: "TERM", Attrs: []string{"name"}, Opr: "ILIKE", Val: "%smith%", Conn: "AND"},
+		{Type: "SIMPLE", Attr: "id", Opr: "=", Val: "1", Conn: "AND"},
+		{Type: "SIMPLE", Attr: "name", Opr: "ILIKE", Val: "%jo%", Conn: "AND"},
+	}, filters)
+
+	// Los comodines de LIKE se buscan literalmente
+	dataTablesRequest.Search.Value = "50%"
+	dataTablesRequest.Columns[1].Search.Value = "a_b"
+	filters, err = dataTablesRequest.Filters(service)
+	assert.NoError(t, err)
+	assert.Equal(t, `%50\%%`, filters[0].Val)
+	assert.Equal(t, `%a\_b%`, filters[2].Val)
+
+	dataTablesRequest.Columns[1].Searchable = false
+	_, err = dataTablesRequest.Filters(service)
+	assert.ErrorIs(t, err, datatables.ErrNoSearchableColumns)
+
+	dataTablesRequest.Search.Regex = true
+	_, err = dataTablesRequest.Filters(service)
+	assert.EqualError(t, err, "regex search is not supported")
+}
+
+func serve(h http.Handler, method string, query string) *httptest.ResponseRecorder {
+	var r *http.Request
+	if method == http.MethodPost {
+		r = httptest.NewRequest(method, "/users", strings.NewReader(query))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		r = httptest.NewRequest(method, "/users?"+query, nil)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestHandler(t *testing.T) {
+	h := datatables.New(newService(t, "users"), datatables.Options{})
+
+	// Búsqueda global, orden y columnas pedidas: score no se devuelve
+	w := serve(h, http.MethodGet, dataTablesValues([]string{"id", "name", "email"}, map[string]string{
+		"search[value]":    "smith",
+		"order[0][column]": "0",
+		"order[0][dir]":    "desc",
+	}).Encode())
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"draw": 3,
+		"recordsTotal": 3,
+		"recordsFiltered": 2,
+		"data": [
+			{"id": 3, "name": "Bob Smith", "email": "bob@test.com"},
+			{"id": 1, "name": "John Smith", "email": "john@mail.com"}
+		]
+	}`, w.Body.String())
+
+	// POST con formulario, paginación y búsqueda por columna
+	w = serve(h, http.MethodPost, dataTablesValues([]string{"id", "email"}, map[string]string{
+		"start":                     "1",
+		"length":                    "1",
+		"columns[1][search][value]": "mail.com",
+		"order[0][column]":          "0",
+		"order[0][dir]":             "asc",
+	}).Encode())
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"draw": 3, "recordsTotal": 3, "recordsFiltered": 2, "data": [{"id": 2, "email": "jane@mail.com"}]}`, w.Body.String())
+
+	// "_" no actúa como comodín
+	w = serve(h, http.MethodGet, dataTablesValues([]string{"id", "name"}, map[string]string{"search[value]": "_"}).Encode())
+	assert.JSONEq(t, `{"draw": 3, "recordsTotal": 3, "recordsFiltered": 0, "data": []}`, w.Body.String())
+
+	// Sin columnas buscables la búsqueda global no coincide con ninguna fila
+	w = serve(h, http.MethodGet, dataTablesValues([]string{"id", "name"}, map[string]string{
+		"search[value]":          "smith",
+		"columns[0][searchable]": "false",
+		"columns[1][searchable]": "false",
+	}).Encode())
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"draw": 3, "recordsTotal": 3, "recordsFiltered": 0, "data": []}`, w.Body.String())
+
+	// La búsqueda en columnas no buscables o que el servicio no filtra se ignora
+	w = serve(h, http.MethodGet, dataTablesValues([]string{"id", "name", "password"}, map[string]string{
+		"columns[1][searchable]":    "false",
+		"columns[1][search][value]": "Jane",
+		"columns[2][search][value]": "x",
+		"order[0][column]":          "0",
+	}).Encode())
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"draw": 3, "recordsTotal": 3, "recordsFiltered": 3, "data": [{"id": 1, "name": "John Smith"}, {"id": 2, "name": "Jane Doe"}, {"id": 3, "name": "Bob Smith"}]}`, w.Body.String())
+
+	// length=-1 devuelve todas las filas
+	w = serve(h, http.MethodGet, dataTablesValues([]string{"id"}, map[string]string{"length": "-1"}).Encode())
+	assert.JSONEq(t, `{"draw": 3, "recordsTotal": 3, "recordsFiltered": 3, "data": [{"id": 1}, {"id": 2}, {"id": 3}]}`, w.Body.String())
+}
+
+func TestHandler_Errors(t *testing.T) {
+	h := datatables.New(newService(t, "users"), datatables.Options{})
+
+	w := serve(h, http.MethodGet, dataTablesValues([]string{"id", "name"}, map[string]string{"columns[1][search][regex]": "true", "columns[1][search][value]": "^J"}).Encode())
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"draw": 3, "recordsTotal": 0, "recordsFiltered": 0, "data": [], "error": "regex search is not supported"}`, w.Body.String())
+
+	// Las columnas excluidas no se devuelven ni se buscan
+	h = datatables.New(newService(t, "users"), datatables.Options{Exclusions: []string{"email"}})
+	w = serve(h, http.MethodGet, dataTablesValues([]string{"email"}, nil).Encode())
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"draw": 3, "recordsTotal": 0, "recordsFiltered": 0, "data": [], "error": "no columns to select"}`, w.Body.String())
+
+	w = serve(h, http.MethodGet, dataTablesValues([]string{"id", "email"}, map[string]string{"columns[1][search][value]": "mail.com"}).Encode())
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"draw": 3, "recordsTotal": 0, "recordsFiltered": 0, "data": [], "error": "attribute search 'email' is not allowed"}`, w.Body.String())
+
+	w = serve(h, http.MethodGet, dataTablesValues([]string{"id", "name", "email"}, map[string]string{"search[value]": "test.com"}).Encode())
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"draw": 3, "recordsTotal": 3, "recordsFiltered": 0, "data": []}`, w.Body.String())
+
+	h = datatables.New(newService(t, "users"), datatables.Options{})
+	w = serve(h, http.MethodGet, "draw=x")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"draw": 0, "recordsTotal": 0, "recordsFiltered": 0, "data": [], "error": "draw must be an integer"}`, w.Body.String())
+
+	var logged error
+	h = datatables.New(newService(t, "missing"), datatables.Options{OnError: func(r *http.Request, err error) { logged = err }})
+	w = serve(h, http.MethodGet, dataTablesValues([]string{"id"}, nil).Encode())
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"draw": 3, "recordsTotal": 0, "recordsFiltered": 0, "data": [], "error": "internal server error"}`, w.Body.String())
+	assert.ErrorContains(t, logged, "no such table")
+}
@@ -0,0 +1,119 @@
+package dialects
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Dialect agrupa las diferencias de sintaxis entre motores de base de datos
+type Dialect interface {
+	// Name es el nombre con el que se registra, coincide con gorm.Dialector.Name()
+	Name() string
+	// Placeholder devuelve el marcador del parámetro en la posición index (empieza en 1)
+	Placeholder(index int) string
+	// QuoteIdentifier encierra entre comillas el nombre de una tabla o columna, escapando las que
+	// contenga. Se usa al generar expresiones a partir de nombres de la base o de los modelos.
+	QuoteIdentifier(identifier string) string
+	// DateRange devuelve la condición "column entre el día from y el final del día to"
+	DateRange(column string, from string, to string) string
+	// CaseInsensitiveLike devuelve una comparación LIKE que no distingue mayúsculas
+	CaseInsensitiveLike(column string, pattern string) string
+	// LikeEscape devuelve la cláusula que define "\" como carácter de escape de LIKE,
+	// vacía si el motor ya lo usa por defecto
+	LikeEscape() string
+	// LimitOffset recibe el ORDER BY ya armado (puede estar vacío) y le agrega la paginación.
+	// Un limit u offset igual a 0 se omite.
+	LimitOffset(order string, limit int, offset int) string
+	// NullsOrder devuelve la clave de orden con NULLS FIRST o NULLS LAST
+	NullsOrder(column string, direction string, nulls string) string
+	// TimeoutHint devuelve el hint a agregar luego del SELECT para limitar su duración
+	TimeoutHint(timeout time.Duration) string
+	// TimeoutStatement devuelve la sentencia a ejecutar en la transacción para limitar su duración
+	TimeoutStatement(timeout time.Duration) string
+	// SupportsCountOver indica si se puede contar con la función de ventana COUNT(*) OVER()
+	SupportsCountOver() bool
+	// SupportsRowValues indica si se pueden comparar filas "(a, b) > (?, ?)"
+	SupportsRowValues() bool
+	// DefaultNullsFirst indica si, sin NULLS FIRST/LAST, el motor ubica los NULL antes que los
+	// demás valores al ordenar en la dirección indicada
+	DefaultNullsFirst(desc bool) bool
+}
+
+// ArgsBinder lo implementan los dialectos que necesitan transformar los argumentos antes de ejecutar
+type ArgsBinder interface {
+	BindArgs(args []any) []any
+}
+
+// Introspector lo implementan los dialectos que pueden listar las columnas de una tabla o vista.
+// La consulta devuelve el nombre y el tipo de dato de cada columna en el orden en que fueron definidas.
+type Introspector interface {
+	ColumnsQuery(table string) (string, []any)
+}
+
+// ArrayComparer lo implementan los dialectos que pueden enlazar toda la lista de un IN como un único arreglo
+type ArrayComparer interface {
+	InArray(column string, placeholder string, not bool) string
+}
+
+var (
+	registryMutex sync.RWMutex
+	registry      = map[string]Dialect{}
+)
+
+func init() {
+	Register(MySQL{})
+	Register(Postgres{})
+	Register(SQLite{})
+	Register(SQLServer{})
+}
+
+// Register agrega o reemplaza un dialecto. Debe llamarse antes de crear los servicios que lo usan.
+func Register(dialect Dialect) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	registry[dialect.Name()] = dialect
+}
+
+// Get devuelve el dialecto registrado con el nombre indicado
+func Get(name string) (Dialect, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	dialect, exists := registry[name]
+	return dialect, exists
+}
+
+// For devuelve el dialecto de la conexión; si no está registrado se usa Postgres
+func For(db *gorm.DB) Dialect {
+	if dialect, exists := Get(db.Dialector.Name()); exists {
+		return dialect
+	}
+	return Postgres{}
+}
+
+// limitOffset arma "ORDER BY ... LIMIT n OFFSET m", común a los motores que la soportan
+func limitOffset(order string, limit int, offset int) string {
+	var parts []string
+	if limit > 0 {
+		parts = append(parts, "LIMIT "+itoa(limit))
+	}
+	if offset > 0 {
+		parts = append(parts, "OFFSET "+itoa(offset))
+	}
+	return order + " " + strings.Join(parts, " ")
+}
+
+// splitTable separa "esquema.tabla", el esquema queda vacío si no se indicó
+func splitTable(table string) (string, string) {
+	if schema, name, found := strings.Cut(table, "."); found {
+		return schema, name
+	}
+	return "", table
+}
+
+func itoa(value int) string {
+	return strconv.Itoa(value)
+}
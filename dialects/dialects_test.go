@@ -0,0 +1,108 @@
+package dialects_test
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/devsstudio/gosql/dialects"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDialects(t *testing.T) {
+	mysql, _ := dialects.Get("mysql")
+	postgres, _ := dialects.Get("postgres")
+	sqlite, _ := dialects.Get("sqlite")
+
+	assert.Equal(t, "?", mysql.Placeholder(3))
+	assert.Equal(t, "$3", postgres.Placeholder(3))
+	assert.Equal(t, "?", sqlite.Placeholder(3))
+
+	assert.Equal(t, "`a``b`", mysql.QuoteIdentifier("a`b"))
+	assert.Equal(t, `"a""b"`, postgres.QuoteIdentifier(`a"b`))
+
+	assert.Equal(t, "d BETWEEN ? AND DATE_ADD(?, INTERVAL 1 DAY)", mysql.DateRange("d", "?", "?"))
+	assert.Equal(t, "d BETWEEN ($1)::TIMESTAMP AND ($2)::TIMESTAMP + interval '1 days'", postgres.DateRange("d", "$1", "$2"))
+	assert.Equal(t, "datetime(d) BETWEEN datetime(?) AND datetime(?, '+1 day')", sqlite.DateRange("d", "?", "?"))
+
+	assert.Equal(t, "LOWER(n) LIKE LOWER(?)", mysql.CaseInsensitiveLike("n", "?"))
+	assert.Equal(t, "n ILIKE $1", postgres.CaseInsensitiveLike("n", "$1"))
+	assert.Equal(t, "n LIKE ? COLLATE NOCASE", sqlite.CaseInsensitiveLike("n", "?"))
+
+	assert.Equal(t, "", mysql.LikeEscape())
+	assert.Equal(t, "", postgres.LikeEscape())
+	assert.Equal(t, ` ESCAPE '\'`, sqlite.LikeEscape())
+
+	assert.Equal(t, "ORDER BY n LIMIT 10 OFFSET 20", mysql.LimitOffset("ORDER BY n", 10, 20))
+	assert.Equal(t, " LIMIT 10", postgres.LimitOffset("", 10, 0))
+	assert.Equal(t, " LIMIT -1 OFFSET 5", sqlite.LimitOffset("", 0, 5))
+
+	assert.Equal(t, "n IS NULL DESC, n ASC", mysql.NullsOrder("n", "ASC", "NULLS FIRST"))
+	assert.Equal(t, "n DESC NULLS LAST", postgres.NullsOrder("n", "DESC", "NULLS LAST"))
+
+	assert.Equal(t, "/*+ MAX_EXECUTION_TIME(1500) */ ", mysql.TimeoutHint(1500*time.Millisecond))
+	assert.Equal(t, "SET LOCAL statement_timeout = 1500", postgres.TimeoutStatement(1500*time.Millisecond))
+	assert.Equal(t, "", sqlite.TimeoutStatement(time.Second))
+
+	assert.False(t, mysql.SupportsCountOver())
+	assert.True(t, postgres.SupportsCountOver())
+}
+
+type upperPostgres struct {
+	dialects.Postgres
+}
+
+func (upperPostgres) Name() string {
+	return "upper"
+}
+
+func TestRegister(t *testing.T) {
+	_, exists := dialects.Get("upper")
+	assert.False(t, exists)
+
+	dialects.Register(upperPostgres{})
+
+	dialect, exists := dialects.Get("upper")
+	assert.True(t, exists)
+	assert.Equal(t, "$2", dialect.Placeholder(2))
+}
+
+func TestSQLServer(t *testing.T) {
+	sqlserver, exists := dialects.Get("sqlserver")
+	assert.True(t, exists)
+
+	assert.Equal(t, "@p3", sqlserver.Placeholder(3))
+	assert.Equal(t, "[a]]b]", sqlserver.QuoteIdentifier("a]b"))
+	assert.Equal(t, "d BETWEEN CAST(@p1 AS DATETIME2) AND DATEADD(day, 1, CAST(@p2 AS DATETIME2))", sqlserver.DateRange("d", "@p1", "@p2"))
+	assert.Equal(t, "n COLLATE Latin1_General_CI_AS LIKE @p1", sqlserver.CaseInsensitiveLike("n", "@p1"))
+	assert.Equal(t, ` ESCAPE '\'`, sqlserver.LikeEscape())
+
+	assert.Equal(t, "ORDER BY n OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY", sqlserver.LimitOffset("ORDER BY n", 10, 20))
+	assert.Equal(t, "ORDER BY (SELECT NULL) OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY", sqlserver.LimitOffset("", 10, 0))
+	assert.Equal(t, "ORDER BY (SELECT NULL) OFFSET 5 ROWS", sqlserver.LimitOffset("", 0, 5))
+	assert.Equal(t, "ORDER BY n", sqlserver.LimitOffset("ORDER BY n", 0, 0))
+
+	assert.Equal(t, "CASE WHEN n IS NULL THEN 1 ELSE 0 END, n ASC", sqlserver.NullsOrder("n", "ASC", "NULLS LAST"))
+	assert.False(t, sqlserver.SupportsRowValues())
+
+	args := sqlserver.(dialects.ArgsBinder).BindArgs([]any{"a", 2})
+	assert.Equal(t, []any{sql.Named("p1", "a"), sql.Named("p2", 2)}, args)
+}
+
+func TestColumnsQuery(t *testing.T) {
+	for _, name := range []string{"mysql", "postgres", "sqlite", "sqlserver"} {
+		dialect, _ := dialects.Get(name)
+		_, ok := dialect.(dialects.Introspector)
+		assert.True(t, ok, name)
+	}
+
+	mysql, _ := dialects.Get("mysql")
+	query, args := mysql.(dialects.Introspector).ColumnsQuery("users")
+	assert.Equal(t, "SELECT column_name, data_type FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? ORDER BY ordinal_position", query)
+	assert.Equal(t, []any{"users"}, args)
+
+	sqlserver, _ := dialects.Get("sqlserver")
+	query, args = sqlserver.(dialects.Introspector).ColumnsQuery("dbo.users")
+	assert.Equal(t, "SELECT COLUMN_NAME, DATA_TYPE FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = @p1 AND TABLE_NAME = @p2 ORDER BY ORDINAL_POSITION", query)
+	assert.Equal(t, []any{"dbo", "users"}, args)
+}
@@ -0,0 +1,80 @@
+package dialects
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MySQL implementa Dialect para MySQL y MariaDB
+type MySQL struct{}
+
+func (MySQL) Name() string {
+	return "mysql"
+}
+
+func (MySQL) Placeholder(index int) string {
+	return "?"
+}
+
+func (MySQL) QuoteIdentifier(identifier string) string {
+	return "`" + strings.ReplaceAll(identifier, "`", "``") + "`"
+}
+
+func (MySQL) DateRange(column string, from string, to string) string {
+	return fmt.Sprintf("%s BETWEEN %s AND DATE_ADD(%s, INTERVAL 1 DAY)", column, from, to)
+}
+
+// CaseInsensitiveLike compara en minúsculas porque MySQL no tiene ILIKE y el resultado
+// de LIKE depende de la collation de la columna
+func (MySQL) CaseInsensitiveLike(column string, pattern string) string {
+	return fmt.Sprintf("LOWER(%s) LIKE LOWER(%s)", column, pattern)
+}
+
+// LikeEscape es vacío porque MySQL ya escapa con "\" y en sus literales '\' quedaría sin cerrar
+func (MySQL) LikeEscape() string {
+	return ""
+}
+
+func (MySQL) LimitOffset(order string, limit int, offset int) string {
+	return limitOffset(order, limit, offset)
+}
+
+// NullsOrder emula NULLS FIRST/LAST ordenando primero por "IS NULL"
+func (MySQL) NullsOrder(column string, direction string, nulls string) string {
+	if nulls == "NULLS FIRST" {
+		return column + " IS NULL DESC, " + column + " " + direction
+	}
+	return column + " IS NULL ASC, " + column + " " + direction
+}
+
+func (MySQL) ColumnsQuery(table string) (string, []any) {
+	schema, name := splitTable(table)
+	if schema == "" {
+		return "SELECT column_name, data_type FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? ORDER BY ordinal_position", []any{name}
+	}
+	return "SELECT column_name, data_type FROM information_schema.columns WHERE table_schema = ? AND table_name = ? ORDER BY ordinal_position", []any{schema, name}
+}
+
+func (MySQL) TimeoutHint(timeout time.Duration) string {
+	return fmt.Sprintf("/*+ MAX_EXECUTION_TIME(%d) */ ", timeout.Milliseconds())
+}
+
+func (MySQL) TimeoutStatement(timeout time.Duration) string {
+	return ""
+}
+
+// SupportsCountOver es falso para mantener compatibilidad con MySQL 5.7
+func (MySQL) SupportsCountOver() bool {
+	return false
+}
+
+func (MySQL) SupportsRowValues() bool {
+	return true
+}
+
+// DefaultNullsFirst: MySQL compara NULL como menor que cualquier valor, por eso quedan
+// primero en ASC; para invertirlo NullsOrder agrega el "IS NULL" al orden
+func (MySQL) DefaultNullsFirst(desc bool) bool {
+	return !desc
+}
@@ -0,0 +1,80 @@
+package dialects
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Postgres implementa Dialect para PostgreSQL
+type Postgres struct{}
+
+func (Postgres) Name() string {
+	return "postgres"
+}
+
+func (Postgres) Placeholder(index int) string {
+	return "$" + itoa(index)
+}
+
+func (Postgres) QuoteIdentifier(identifier string) string {
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
+
+func (Postgres) DateRange(column string, from string, to string) string {
+	return fmt.Sprintf("%s BETWEEN (%s)::TIMESTAMP AND (%s)::TIMESTAMP + interval '1 days'", column, from, to)
+}
+
+func (Postgres) CaseInsensitiveLike(column string, pattern string) string {
+	return column + " ILIKE " + pattern
+}
+
+// LikeEscape es vacío porque "\" es el escape por defecto de LIKE en PostgreSQL
+func (Postgres) LikeEscape() string {
+	return ""
+}
+
+// InArray evita generar un placeholder por valor en listas grandes
+func (Postgres) InArray(column string, placeholder string, not bool) string {
+	if not {
+		return column + " <> ALL(" + placeholder + ")"
+	}
+	return column + " = ANY(" + placeholder + ")"
+}
+
+func (Postgres) LimitOffset(order string, limit int, offset int) string {
+	return limitOffset(order, limit, offset)
+}
+
+func (Postgres) NullsOrder(column string, direction string, nulls string) string {
+	return column + " " + direction + " " + nulls
+}
+
+func (Postgres) ColumnsQuery(table string) (string, []any) {
+	schema, name := splitTable(table)
+	if schema == "" {
+		return "SELECT column_name, data_type FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = $1 ORDER BY ordinal_position", []any{name}
+	}
+	return "SELECT column_name, data_type FROM information_schema.columns WHERE table_schema = $1 AND table_name = $2 ORDER BY ordinal_position", []any{schema, name}
+}
+
+func (Postgres) TimeoutHint(timeout time.Duration) string {
+	return ""
+}
+
+func (Postgres) TimeoutStatement(timeout time.Duration) string {
+	return fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout.Milliseconds())
+}
+
+func (Postgres) SupportsCountOver() bool {
+	return true
+}
+
+func (Postgres) SupportsRowValues() bool {
+	return true
+}
+
+// DefaultNullsFirst trata los NULL como el mayor valor: último en ASC, primero en DESC
+func (Postgres) DefaultNullsFirst(desc bool) bool {
+	return desc
+}
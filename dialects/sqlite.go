@@ -0,0 +1,80 @@
+package dialects
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SQLite implementa Dialect para SQLite 3.30 o superior
+type SQLite struct{}
+
+func (SQLite) Name() string {
+	return "sqlite"
+}
+
+func (SQLite) Placeholder(index int) string {
+	return "?"
+}
+
+func (SQLite) QuoteIdentifier(identifier string) string {
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
+
+// DateRange normaliza con datetime() porque SQLite guarda las fechas como texto
+func (SQLite) DateRange(column string, from string, to string) string {
+	return fmt.Sprintf("datetime(%s) BETWEEN datetime(%s) AND datetime(%s, '+1 day')", column, from, to)
+}
+
+func (SQLite) CaseInsensitiveLike(column string, pattern string) string {
+	return column + " LIKE " + pattern + " COLLATE NOCASE"
+}
+
+// LikeEscape es necesario porque SQLite no tiene un carácter de escape por defecto
+func (SQLite) LikeEscape() string {
+	return ` ESCAPE '\'`
+}
+
+func (SQLite) LimitOffset(order string, limit int, offset int) string {
+	// SQLite no admite OFFSET sin LIMIT
+	if limit <= 0 && offset > 0 {
+		return order + " LIMIT -1 OFFSET " + itoa(offset)
+	}
+	return limitOffset(order, limit, offset)
+}
+
+func (SQLite) NullsOrder(column string, direction string, nulls string) string {
+	return column + " " + direction + " " + nulls
+}
+
+// ColumnsQuery usa la función pragma_table_info, disponible desde SQLite 3.16
+func (SQLite) ColumnsQuery(table string) (string, []any) {
+	schema, name := splitTable(table)
+	if schema == "" {
+		return "SELECT name, type FROM pragma_table_info(?) ORDER BY cid", []any{name}
+	}
+	return "SELECT name, type FROM pragma_table_info(?, ?) ORDER BY cid", []any{name, schema}
+}
+
+// SQLite no tiene límite de tiempo del lado del servidor, se usa solo el contexto
+func (SQLite) TimeoutHint(timeout time.Duration) string {
+	return ""
+}
+
+func (SQLite) TimeoutStatement(timeout time.Duration) string {
+	return ""
+}
+
+func (SQLite) SupportsCountOver() bool {
+	return true
+}
+
+func (SQLite) SupportsRowValues() bool {
+	return true
+}
+
+// DefaultNullsFirst: SQLite ubica los NULL antes que los números, textos y blobs, así que
+// quedan primero en ASC y últimos en DESC mientras no se pida NULLS FIRST/LAST
+func (SQLite) DefaultNullsFirst(desc bool) bool {
+	return !desc
+}
@@ -0,0 +1,100 @@
+package dialects
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SQLServer implementa Dialect para SQL Server 2012 o superior
+type SQLServer struct{}
+
+func (SQLServer) Name() string {
+	return "sqlserver"
+}
+
+func (SQLServer) Placeholder(index int) string {
+	return "@p" + itoa(index)
+}
+
+// BindArgs nombra los argumentos "p1", "p2"... porque GORM interpreta "@p1" como parámetro con nombre
+func (SQLServer) BindArgs(args []any) []any {
+	named := make([]any, len(args))
+	for i, arg := range args {
+		named[i] = sql.Named("p"+itoa(i+1), arg)
+	}
+	return named
+}
+
+func (SQLServer) QuoteIdentifier(identifier string) string {
+	return "[" + strings.ReplaceAll(identifier, "]", "]]") + "]"
+}
+
+func (SQLServer) DateRange(column string, from string, to string) string {
+	return fmt.Sprintf("%s BETWEEN CAST(%s AS DATETIME2) AND DATEADD(day, 1, CAST(%s AS DATETIME2))", column, from, to)
+}
+
+// CaseInsensitiveLike fuerza una collation CI porque el resultado de LIKE depende de la collation de la columna
+func (SQLServer) CaseInsensitiveLike(column string, pattern string) string {
+	return fmt.Sprintf("%s COLLATE Latin1_General_CI_AS LIKE %s", column, pattern)
+}
+
+// LikeEscape es necesario porque SQL Server no tiene un carácter de escape por defecto
+func (SQLServer) LikeEscape() string {
+	return ` ESCAPE '\'`
+}
+
+// LimitOffset usa OFFSET/FETCH, que exige un ORDER BY; sin orden se ordena por una constante
+func (SQLServer) LimitOffset(order string, limit int, offset int) string {
+	if limit <= 0 && offset <= 0 {
+		return order
+	}
+	if order == "" {
+		order = "ORDER BY (SELECT NULL)"
+	}
+	if limit <= 0 {
+		return fmt.Sprintf("%s OFFSET %d ROWS", order, offset)
+	}
+	return fmt.Sprintf("%s OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", order, offset, limit)
+}
+
+// NullsOrder emula NULLS FIRST/LAST con un CASE porque SQL Server no soporta la cláusula
+func (SQLServer) NullsOrder(column string, direction string, nulls string) string {
+	if nulls == "NULLS FIRST" {
+		return "CASE WHEN " + column + " IS NULL THEN 0 ELSE 1 END, " + column + " " + direction
+	}
+	return "CASE WHEN " + column + " IS NULL THEN 1 ELSE 0 END, " + column + " " + direction
+}
+
+func (SQLServer) ColumnsQuery(table string) (string, []any) {
+	schema, name := splitTable(table)
+	if schema == "" {
+		return "SELECT COLUMN_NAME, DATA_TYPE FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = SCHEMA_NAME() AND TABLE_NAME = @p1 ORDER BY ORDINAL_POSITION", []any{name}
+	}
+	return "SELECT COLUMN_NAME, DATA_TYPE FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = @p1 AND TABLE_NAME = @p2 ORDER BY ORDINAL_POSITION", []any{schema, name}
+}
+
+// SQL Server no tiene límite de tiempo por sentencia, se usa solo el contexto
+func (SQLServer) TimeoutHint(timeout time.Duration) string {
+	return ""
+}
+
+func (SQLServer) TimeoutStatement(timeout time.Duration) string {
+	return ""
+}
+
+func (SQLServer) SupportsCountOver() bool {
+	return true
+}
+
+// SupportsRowValues es falso, SQL Server no compara filas "(a, b) > (?, ?)"
+func (SQLServer) SupportsRowValues() bool {
+	return false
+}
+
+// DefaultNullsFirst: SQL Server considera NULL el valor más bajo del orden (primero en ASC),
+// la única forma de moverlos es el CASE de NullsOrder
+func (SQLServer) DefaultNullsFirst(desc bool) bool {
+	return !desc
+}
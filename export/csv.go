@@ -0,0 +1,37 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/services"
+)
+
+// WriteCSV escribe el listado como CSV. La primera fila tiene el Label de cada columna.
+func WriteCSV(writer io.Writer, service *services.Pagination, filters []request.FilterRequest, findRequest request.FindRequest, exclusions *[]string) error {
+	csvWriter := csv.NewWriter(writer)
+
+	err := export(service, filters, findRequest, exclusions,
+		func(columns []column) error {
+			header := make([]string, len(columns))
+			for i, col := range columns {
+				header[i] = col.label
+			}
+			return csvWriter.Write(header)
+		},
+		func(columns []column, row map[string]any) error {
+			record := make([]string, len(columns))
+			for i, col := range columns {
+				record[i] = format(row[col.alias])
+			}
+			return csvWriter.Write(record)
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
@@ -0,0 +1,80 @@
+// Package export escribe el resultado de un listado de Pagination en CSV, NDJSON o XLSX.
+// Las filas se leen con Stream, de a una, por lo que la memoria no depende del tamaño del resultado.
+//
+// Los valores se escriben tal como los normaliza Pagination (o el Converter de la columna):
+// las fechas como "2006-01-02", los timestamps en RFC 3339 con fracción de segundos si la
+// tienen, los decimales sin perder precisión y los NULL como celda vacía.
+package export
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/services"
+)
+
+// column es una columna del resultado con su encabezado
+type column struct {
+	alias string
+	label string
+}
+
+// export recorre la consulta y entrega cada fila a writeRow, luego de writeHeader
+func export(service *services.Pagination, filters []request.FilterRequest, findRequest request.FindRequest, exclusions *[]string, writeHeader func(columns []column) error, writeRow func(columns []column, row map[string]any) error) error {
+	stream, err := service.Stream(filters, findRequest, exclusions)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	columns := getColumns(service, stream.Columns())
+	if err := writeHeader(columns); err != nil {
+		return err
+	}
+
+	for stream.Next() {
+		if err := writeRow(columns, stream.Row()); err != nil {
+			return err
+		}
+	}
+	return stream.Err()
+}
+
+func getColumns(service *services.Pagination, aliases []string) []column {
+	columns := make([]column, 0, len(aliases))
+	for _, alias := range aliases {
+		col := column{alias: alias, label: alias}
+		if def, exists := service.ColumnDef(alias); exists {
+			if def.Label != "" {
+				col.label = def.Label
+			}
+		}
+		columns = append(columns, col)
+	}
+	return columns
+}
+
+// format devuelve como texto el valor ya normalizado por Stream
+func format(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(data)
+	}
+}
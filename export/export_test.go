@@ -0,0 +1,106 @@
+package export_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/devsstudio/gosql/export"
+	"github.com/devsstudio/gosql/internal/testdb"
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/services"
+	"github.com/devsstudio/gosql/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newService(t *testing.T) *services.Pagination {
+	statements := []string{
+		`CREATE TABLE payments (id INTEGER PRIMARY KEY, customer TEXT, amount NUMERIC, paid BOOLEAN, paid_at DATETIME, due DATE)`,
+		`INSERT INTO payments VALUES (1, 'Acme, Inc.', 10.5, 1, '2024-01-15 10:30:00', '2024-01-31')`,
+		`INSERT INTO payments VALUES (2, '<Bob & "Co">', 20, 0, NULL, NULL)`,
+		`INSERT INTO payments VALUES (3, 'Carol', 5, 1, '2024-02-01 08:15:30.125', '2024-02-29')`,
+	}
+
+	customer := types.NewColumnDef("customer", "customer", types.ColumnTypeText)
+	customer.Label = "Customer"
+	amount := types.NewColumnDef("amount", "amount", types.ColumnTypeDecimal)
+	amount.Label = "Amount"
+
+	return testdb.NewService(t, statements, types.ListParams{
+		Table: "payments",
+		ColumnDefs: []types.ColumnDef{
+			types.NewColumnDef("id", "id", types.ColumnTypeInt),
+			customer,
+			amount,
+			types.NewColumnDef("paid", "paid", types.ColumnTypeBool),
+			types.NewColumnDef("paidAt", "paid_at", types.ColumnTypeTimestamp),
+			types.NewColumnDef("due", "due", types.ColumnTypeDate),
+		},
+	})
+}
+
+var findRequest = request.FindRequest{Order: types.Order{{Column: "id", Direction: "asc"}}}
+
+func TestWriteCSV(t *testing.T) {
+	var buffer bytes.Buffer
+	err := export.WriteCSV(&buffer, newService(t), []request.FilterRequest{}, findRequest, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "id,Customer,Amount,paid,paidAt,due\n"+
+		"1,\"Acme, Inc.\",10.5,true,2024-01-15T10:30:00Z,2024-01-31\n"+
+		"2,\"<Bob & \"\"Co\"\">\",20,false,,\n"+
+		"3,Carol,5,true,2024-02-01T08:15:30.125Z,2024-02-29\n", buffer.String())
+}
+
+func TestWriteCSVTimestamp(t *testing.T) {
+	// Los timestamps conservan la fracción de segundos y las fechas se escriben sin hora
+	var buffer bytes.Buffer
+	filters := []request.FilterRequest{{Type: "IN", Attr: "id", Vals: []string{"1", "3"}}}
+	err := export.WriteCSV(&buffer, newService(t), filters, findRequest, &[]string{"customer", "amount", "paid"})
+	assert.NoError(t, err)
+	assert.Equal(t, "id,paidAt,due\n"+
+		"1,2024-01-15T10:30:00Z,2024-01-31\n"+
+		"3,2024-02-01T08:15:30.125Z,2024-02-29\n", buffer.String())
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	var buffer bytes.Buffer
+	err := export.WriteNDJSON(&buffer, newService(t), []request.FilterRequest{{Attr: "id", Val: "1"}}, findRequest, &[]string{"paid"})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"id":1,"customer":"Acme, Inc.","amount":10.5,"paidAt":"2024-01-15T10:30:00Z","due":"2024-01-31"}`+"\n", buffer.String())
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var buffer bytes.Buffer
+	err := export.WriteXLSX(&buffer, newService(t), []request.FilterRequest{}, findRequest, &[]string{"paidAt", "due"})
+	assert.NoError(t, err)
+
+	reader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+	require.NoError(t, err)
+
+	names := []string{}
+	var sheet string
+	for _, file := range reader.File {
+		names = append(names, file.Name)
+		if file.Name == "xl/worksheets/sheet1.xml" {
+			content, err := file.Open()
+			require.NoError(t, err)
+			data, err := io.ReadAll(content)
+			require.NoError(t, err)
+			sheet = string(data)
+		}
+	}
+	assert.Equal(t, []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"}, names)
+	assert.Contains(t, sheet, `<row><c t="inlineStr"><is><t xml:space="preserve">id</t></is></c><c t="inlineStr"><is><t xml:space="preserve">Customer</t></is></c>`)
+	assert.Contains(t, sheet, `<row><c t="n"><v>1</v></c><c t="inlineStr"><is><t xml:space="preserve">Acme, Inc.</t></is></c><c t="n"><v>10.5</v></c><c t="b"><v>1</v></c></row>`)
+	assert.Contains(t, sheet, `<t xml:space="preserve">&lt;Bob &amp; &#34;Co&#34;&gt;</t>`)
+	assert.Contains(t, sheet, "</sheetData></worksheet>")
+}
+
+func TestExportInvalidFilter(t *testing.T) {
+	var buffer bytes.Buffer
+	err := export.WriteCSV(&buffer, newService(t), []request.FilterRequest{{Attr: "unknown", Val: "1"}}, findRequest, nil)
+	assert.EqualError(t, err, "attribute filter 'unknown' is not allowed")
+	assert.Empty(t, buffer.String())
+}
@@ -0,0 +1,45 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/services"
+)
+
+// WriteNDJSON escribe un objeto JSON por línea. Las claves mantienen el orden de las columnas.
+func WriteNDJSON(writer io.Writer, service *services.Pagination, filters []request.FilterRequest, findRequest request.FindRequest, exclusions *[]string) error {
+	var line bytes.Buffer
+
+	return export(service, filters, findRequest, exclusions,
+		func(columns []column) error {
+			return nil
+		},
+		func(columns []column, row map[string]any) error {
+			line.Reset()
+			line.WriteByte('{')
+			for i, col := range columns {
+				if i > 0 {
+					line.WriteByte(',')
+				}
+				key, err := json.Marshal(col.alias)
+				if err != nil {
+					return err
+				}
+				value, err := json.Marshal(row[col.alias])
+				if err != nil {
+					return err
+				}
+				line.Write(key)
+				line.WriteByte(':')
+				line.Write(value)
+			}
+			line.WriteString("}\n")
+
+			_, err := writer.Write(line.Bytes())
+			return err
+		},
+	)
+}
@@ -0,0 +1,112 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/services"
+)
+
+// Partes fijas del libro, la única hoja se escribe fila por fila
+var xlsxParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// WriteXLSX escribe el listado como un libro de Excel con una hoja. Los números y booleanos
+// se guardan como celdas numéricas/lógicas y el resto como texto, las fechas con el mismo
+// formato que en CSV.
+func WriteXLSX(writer io.Writer, service *services.Pagination, filters []request.FilterRequest, findRequest request.FindRequest, exclusions *[]string) error {
+	zipWriter := zip.NewWriter(writer)
+
+	for _, part := range xlsxParts {
+		file, err := zipWriter.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(file, part.content); err != nil {
+			return err
+		}
+	}
+
+	sheet, err := zipWriter.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+
+	var row strings.Builder
+	err = export(service, filters, findRequest, exclusions,
+		func(columns []column) error {
+			row.Reset()
+			row.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`)
+			row.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData><row>`)
+			for _, col := range columns {
+				writeXLSXCell(&row, col.label)
+			}
+			row.WriteString("</row>")
+			_, err := io.WriteString(sheet, row.String())
+			return err
+		},
+		func(columns []column, values map[string]any) error {
+			row.Reset()
+			row.WriteString("<row>")
+			for _, col := range columns {
+				writeXLSXCell(&row, values[col.alias])
+			}
+			row.WriteString("</row>")
+			_, err := io.WriteString(sheet, row.String())
+			return err
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(sheet, "</sheetData></worksheet>"); err != nil {
+		return err
+	}
+	return zipWriter.Close()
+}
+
+func writeXLSXCell(builder *strings.Builder, value any) {
+	switch v := value.(type) {
+	case nil:
+		builder.WriteString("<c/>")
+	case int64, float64, json.Number:
+		builder.WriteString(`<c t="n"><v>` + format(v) + "</v></c>")
+	case bool:
+		if v {
+			builder.WriteString(`<c t="b"><v>1</v></c>`)
+		} else {
+			builder.WriteString(`<c t="b"><v>0</v></c>`)
+		}
+	default:
+		builder.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+		xml.EscapeText(builder, []byte(format(v)))
+		builder.WriteString("</t></is></c>")
+	}
+}
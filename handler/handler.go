@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/devsstudio/gosql/constants"
+	"github.com/devsstudio/gosql/helpers"
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/response"
+	"github.com/devsstudio/gosql/services"
+)
+
+// Options configura las rutas que expone el handler
+type Options struct {
+	Exclusions   []string                         // Columnas que nunca se devuelven ni se pueden filtrar
+	MaxLimit     int                              // Filas máximas de la ruta "/", 0 usa constants.HANDLER_MAX_LIMIT
+	Select2Value string                           // Columna usada como value en /select2, vacío deshabilita la ruta
+	Select2Text  string                           // Columna usada como label en /select2
+	OnError      func(r *http.Request, err error) // Recibe los errores de la base de datos de cualquier ruta, el cliente solo ve "internal server error"
+}
+
+type paginationHandler struct {
+	service *services.Pagination
+	options Options
+}
+
+// New crea un http.Handler que expone el listado en las sub-rutas:
+//
+//	/           FindAll             []item (hasta Options.MaxLimit filas)
+//	/paginated  FindPaginated       response.PaginationResponse
+//	/offset     FindPaginatedOffset response.PaginationOffsetResponse
+//	/count      Count               response.CountResponse
+//	/select2    FindSelect2         response.Select2Response
+//
+// Las rutas son relativas, se monta con http.StripPrefix (p.e. mux.Handle("/users/", http.StripPrefix("/users", h))).
+// Aceptan GET con los parámetros en la query string o POST con un cuerpo JSON (ver request.FromHTTPRequest).
+// Las solicitudes inválidas responden 400 y los errores de la base de datos 500, ambos con un response.ErrorResponse.
+func New(service *services.Pagination, options Options) http.Handler {
+	return &paginationHandler{service: service, options: options}
+}
+
+func (handler *paginationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		WriteJSON(w, http.StatusMethodNotAllowed, response.ErrorResponse{Status: http.StatusMethodNotAllowed, Message: "method not allowed"})
+		return
+	}
+
+	route := strings.Trim(r.URL.Path, "/")
+	if !handler.hasRoute(route) {
+		WriteJSON(w, http.StatusNotFound, response.ErrorResponse{Status: http.StatusNotFound, Message: "route '" + r.URL.Path + "' not found"})
+		return
+	}
+
+	listRequest, err := request.FromHTTPRequest(r)
+	if err != nil {
+		handler.writeError(w, r, err)
+		return
+	}
+
+	exclusions := append([]string{}, handler.options.Exclusions...)
+	if err := checkExclusions(listRequest.Filters, exclusions); err != nil {
+		handler.writeError(w, r, err)
+		return
+	}
+
+	service := handler.service.WithContext(r.Context())
+
+	var result any
+	switch route {
+	case "":
+		result, err = service.FindAll(listRequest.Filters, handler.getFindRequest(listRequest), &exclusions)
+	case "paginated":
+		result, err = service.FindPaginated(listRequest.Filters, listRequest.PaginationRequest, &exclusions)
+	case "offset":
+		result, err = service.FindPaginatedOffset(listRequest.Filters, listRequest.PaginationOffset(), &exclusions)
+	case "count":
+		var count int
+		count, err = service.Count(listRequest.Filters)
+		result = response.CountResponse{Count: count}
+	case "select2":
+		infiniteScroll := request.InfiniteScrollRequest{Page: listRequest.Page, Limit: listRequest.Limit, Order: listRequest.Order}
+		result, err = service.FindSelect2(listRequest.Filters, infiniteScroll, handler.options.Select2Value, handler.options.Select2Text)
+	}
+	if err != nil {
+		handler.writeError(w, r, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, result)
+}
+
+func (handler *paginationHandler) hasRoute(route string) bool {
+	switch route {
+	case "", "paginated", "offset", "count":
+		return true
+	case "select2":
+		return handler.options.Select2Value != "" && handler.options.Select2Text != ""
+	}
+	return false
+}
+
+// getFindRequest limita las filas de la ruta "/", sin limit se devuelve el máximo
+func (handler *paginationHandler) getFindRequest(listRequest *request.ListRequest) request.FindRequest {
+	maxLimit := handler.options.MaxLimit
+	if maxLimit <= 0 {
+		maxLimit = constants.HANDLER_MAX_LIMIT
+	}
+	findRequest := listRequest.Find()
+	if findRequest.Limit <= 0 || findRequest.Limit > maxLimit {
+		findRequest.Limit = maxLimit
+	}
+	return findRequest
+}
+
+// checkExclusions rechaza los filtros sobre columnas excluidas, incluso dentro de los grupos,
+// porque permitirían deducir sus valores aunque no se devuelvan
+func checkExclusions(filters []request.FilterRequest, exclusions []string) error {
+	for _, filter := range filters {
+		attrs := append([]string{filter.Attr}, filter.Attrs...)
+		if filter.Type == "COLUMN" {
+			attrs = append(attrs, filter.Val)
+		}
+		for _, attr := range attrs {
+			if helpers.ArrayContains(exclusions, attr) {
+				return errors.New("attribute filter '" + attr + "' is not allowed")
+			}
+		}
+		if err := checkExclusions(filter.Filters, exclusions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ErrorStatus devuelve el código HTTP de un error del servicio: 500 ante los errores de la base
+// de datos, que además se informan a onError (puede ser nil), y 400 ante cualquier otro error,
+// que son los de validación de la solicitud. Lo comparten los adaptadores HTTP del módulo.
+func ErrorStatus(r *http.Request, err error, onError func(r *http.Request, err error)) int {
+	var queryError *services.QueryError
+	if !errors.As(err, &queryError) {
+		return http.StatusBadRequest
+	}
+	if onError != nil {
+		onError(r, err)
+	}
+	return http.StatusInternalServerError
+}
+
+// writeError responde según ErrorStatus, sin exponer el detalle de los errores de la base de datos
+func (handler *paginationHandler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	if ErrorStatus(r, err, handler.options.OnError) == http.StatusInternalServerError {
+		WriteJSON(w, http.StatusInternalServerError, response.ErrorResponse{Status: http.StatusInternalServerError, Message: "internal server error"})
+		return
+	}
+
+	errorResponse := response.ErrorResponse{Status: http.StatusBadRequest, Message: err.Error()}
+	var validationError *request.ValidationError
+	if errors.As(err, &validationError) {
+		errorResponse.Message = "invalid request"
+		errorResponse.Errors = validationError.Errors
+	}
+	WriteJSON(w, http.StatusBadRequest, errorResponse)
+}
+
+// WriteJSON responde con body codificado en JSON. Usa application/json salvo que ya se haya
+// definido otro Content-Type, como hace el adaptador OData. Lo comparten los adaptadores HTTP.
+func WriteJSON(w http.ResponseWriter, status int, body any) {
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	}
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,160 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devsstudio/gosql/handler"
+	"github.com/devsstudio/gosql/internal/testdb"
+	"github.com/devsstudio/gosql/response"
+	"github.com/devsstudio/gosql/services"
+	"github.com/devsstudio/gosql/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newService(t *testing.T, table string) *services.Pagination {
+	statements := []string{
+		`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, status TEXT, password TEXT)`,
+		`INSERT INTO users VALUES (1, 'John', 'A', 'x'), (2, 'Jane', 'A', 'y'), (3, 'Bob', 'I', 'z')`,
+	}
+
+	return testdb.NewService(t, statements, types.ListParams{
+		Table: table,
+		ColumnDefs: []types.ColumnDef{
+			types.NewColumnDef("id", "id", types.ColumnTypeInt),
+			types.NewColumnDef("name", "name", types.ColumnTypeText),
+			types.NewColumnDef("status", "status", types.ColumnTypeText),
+			types.NewColumnDef("password", "password", types.ColumnTypeText),
+		},
+	})
+}
+
+func serve(h http.Handler, method string, target string, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, nil)
+	if body != "" {
+		r = httptest.NewRequest(method, target, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestHandler_Routes(t *testing.T) {
+	h := handler.New(newService(t, "users"), handler.Options{
+		Exclusions:   []string{"password"},
+		Select2Value: "id",
+		Select2Text:  "name",
+	})
+
+	w := serve(h, http.MethodGet, "/?filter=status:eq:A&order=id:desc", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `[{"id": 2, "name": "Jane", "status": "A"}, {"id": 1, "name": "John", "status": "A"}]`, w.Body.String())
+
+	// Los filtros sobre columnas excluidas se rechazan, también dentro de un grupo
+	w = serve(h, http.MethodGet, "/?filter=password:like:x%25", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"status": 400, "message": "attribute filter 'password' is not allowed"}`, w.Body.String())
+
+	w = serve(h, http.MethodPost, "/count", `{"filters": [{"type": "GROUP", "filters": [{"type": "COLUMN", "attr": "name", "val": "password"}]}]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"status": 400, "message": "attribute filter 'password' is not allowed"}`, w.Body.String())
+
+	w = serve(h, http.MethodGet, "/paginated?page=2&limit=2&count=true&order[id]=asc", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var paginationResponse response.PaginationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &paginationResponse))
+	assert.Equal(t, 2, paginationResponse.Page)
+	assert.Equal(t, float64(3), paginationResponse.TotalItems)
+	assert.Equal(t, 2, paginationResponse.TotalPages)
+	assert.Len(t, paginationResponse.Items, 1)
+
+	w = serve(h, http.MethodPost, "/offset", `{"offset": 1, "limit": 1, "order": {"id": "asc"}}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var offsetResponse response.PaginationOffsetResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &offsetResponse))
+	assert.Equal(t, 1, offsetResponse.Offset)
+	assert.Equal(t, float64(2), offsetResponse.Items[0]["id"])
+
+	w = serve(h, http.MethodGet, "/count?filters[0][attr]=status&filters[0][val]=I", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"count": 1}`, w.Body.String())
+
+	w = serve(h, http.MethodGet, "/select2/?filter=name:like:J%25&order=id", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"items": [{"value": 1, "label": "John"}, {"value": 2, "label": "Jane"}]}`, w.Body.String())
+}
+
+func TestHandler_Limits(t *testing.T) {
+	h := handler.New(newService(t, "users"), handler.Options{Exclusions: []string{"password"}, MaxLimit: 2})
+
+	// Sin limit la ruta "/" devuelve como máximo MaxLimit filas
+	w := serve(h, http.MethodGet, "/?order=id", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"id": 1, "name": "John", "status": "A"}, {"id": 2, "name": "Jane", "status": "A"}]`, w.Body.String())
+
+	w = serve(h, http.MethodGet, "/?order=id&limit=1", "")
+	assert.JSONEq(t, `[{"id": 1, "name": "John", "status": "A"}]`, w.Body.String())
+
+	// Si las exclusiones cubren todas las columnas no se devuelve ninguna
+	h = handler.New(newService(t, "users"), handler.Options{Exclusions: []string{"password", "id", "name", "status"}})
+	w = serve(h, http.MethodGet, "/", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"status": 400, "message": "no columns to select"}`, w.Body.String())
+}
+
+func TestHandler_Errors(t *testing.T) {
+	h := handler.New(newService(t, "users"), handler.Options{})
+
+	w := serve(h, http.MethodGet, "/paginated?limit=500&filters[0][attr]=name&filters[0][opr]=regexp", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"status": 400, "message": "invalid request", "errors": [
+		{"field": "filters[0].opr", "message": "must be one of: = <> > >= < <= LIKE ILIKE"},
+		{"field": "limit", "message": "must be less than or equal to 50"}
+	]}`, w.Body.String())
+
+	w = serve(h, http.MethodGet, "/?filter=unknown:eq:1", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"status": 400, "message": "attribute filter 'unknown' is not allowed"}`, w.Body.String())
+
+	w = serve(h, http.MethodGet, "/select2", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serve(h, http.MethodGet, "/unknown", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"status": 404, "message": "route '/unknown' not found"}`, w.Body.String())
+
+	w = serve(h, http.MethodDelete, "/", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET, POST", w.Header().Get("Allow"))
+}
+
+func TestHandler_DatabaseError(t *testing.T) {
+	var logged error
+	h := handler.New(newService(t, "missing"), handler.Options{
+		OnError: func(r *http.Request, err error) { logged = err },
+	})
+
+	w := serve(h, http.MethodGet, "/count", "")
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"status": 500, "message": "internal server error"}`, w.Body.String())
+	var queryError *services.QueryError
+	assert.ErrorAs(t, logged, &queryError)
+	assert.Contains(t, logged.Error(), "no such table")
+}
+
+func TestHandler_Mount(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/users/", http.StripPrefix("/users", handler.New(newService(t, "users"), handler.Options{})))
+
+	w := serve(mux, http.MethodGet, "/users/count", "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"count": 3}`, w.Body.String())
+}
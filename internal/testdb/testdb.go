@@ -0,0 +1,33 @@
+// Package testdb arma las bases SQLite en memoria que usan las pruebas de los paquetes del módulo
+package testdb
+
+import (
+	"testing"
+
+	"github.com/devsstudio/gosql/services"
+	"github.com/devsstudio/gosql/types"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// Open abre una base SQLite en memoria y ejecuta las sentencias indicadas (esquema y datos)
+func Open(t testing.TB, statements ...string) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	// Cada conexión a ":memory:" es una base distinta
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	for _, statement := range statements {
+		require.NoError(t, db.Exec(statement).Error)
+	}
+	return db
+}
+
+// NewService crea el servicio sobre una base abierta con Open
+func NewService(t testing.TB, statements []string, params types.ListParams) *services.Pagination {
+	return services.PaginationService(Open(t, statements...), params)
+}
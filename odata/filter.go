@@ -0,0 +1,453 @@
+package odata
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/devsstudio/gosql/request"
+)
+
+// SyntaxError describe una opción de consulta inválida. Position se cuenta en caracteres
+// desde 1 dentro del valor de la opción, 0 si no aplica.
+type SyntaxError struct {
+	Option   string
+	Position int
+	Message  string
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Position > 0 {
+		return fmt.Sprintf("%s: %s at position %d", e.Option, e.Message, e.Position)
+	}
+	return e.Option + ": " + e.Message
+}
+
+var propertyRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var (
+	// Operadores de comparación de OData y su equivalente SQL
+	comparisonOperators = map[string]string{"eq": "=", "ne": "<>", "gt": ">", "ge": ">=", "lt": "<", "le": "<="}
+	// Operador resultante de intercambiar los lados de la comparación
+	flippedOperators = map[string]string{"eq": "eq", "ne": "ne", "gt": "lt", "ge": "le", "lt": "gt", "le": "ge"}
+	// Patrón LIKE de cada función de texto
+	likeFunctions = map[string]string{"contains": "%%%s%%", "startswith": "%s%%", "endswith": "%%%s"}
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenOpen
+	tokenClose
+	tokenComma
+	tokenString
+	tokenWord
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+type operand struct {
+	property bool
+	null     bool
+	text     string
+}
+
+// filterExpr es un nodo del árbol de $filter
+type filterExpr struct {
+	kind     string // and, or, not, compare, in, function
+	children []*filterExpr
+	left     operand
+	right    operand
+	opr      string
+	values   []string
+	ilike    bool // La función se aplicó sobre tolower/toupper de la propiedad
+	pos      int
+}
+
+type filterParser struct {
+	tokens []token
+	pos    int
+}
+
+// ParseFilter convierte una expresión $filter en los filtros que entiende el servicio. Soporta
+// eq ne gt ge lt le, in, and, or, not, paréntesis y contains/startswith/endswith, también sobre
+// tolower/toupper de la propiedad (ILIKE). Comparar dos propiedades genera un filtro COLUMN
+// y "not" un filtro NOT_GROUP.
+func ParseFilter(expression string) ([]request.FilterRequest, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+	if tokens[0].kind == tokenEOF {
+		return []request.FilterRequest{}, nil
+	}
+
+	parser := &filterParser{tokens: tokens}
+	expr, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if next := parser.peek(); next.kind != tokenEOF {
+		return nil, syntaxError(next.pos, "unexpected token '%s'", next.text)
+	}
+
+	node, err := compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	return node.Filters(), nil
+}
+
+func syntaxError(pos int, format string, args ...any) error {
+	return &SyntaxError{Option: "$filter", Position: pos + 1, Message: fmt.Sprintf(format, args...)}
+}
+
+func tokenize(expression string) ([]token, error) {
+	input := []rune(expression)
+	var tokens []token
+	for pos := 0; pos < len(input); {
+		char := input[pos]
+		switch {
+		case unicode.IsSpace(char):
+			pos++
+		case char == '(':
+			tokens = append(tokens, token{kind: tokenOpen, text: "(", pos: pos})
+			pos++
+		case char == ')':
+			tokens = append(tokens, token{kind: tokenClose, text: ")", pos: pos})
+			pos++
+		case char == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: pos})
+			pos++
+		case char == '\'':
+			// Las comillas simples dentro del texto se escriben duplicadas: 'O''Neil'
+			start := pos
+			var builder strings.Builder
+			for pos++; ; pos++ {
+				if pos >= len(input) {
+					return nil, syntaxError(start, "unterminated string")
+				}
+				if input[pos] == '\'' {
+					if pos+1 < len(input) && input[pos+1] == '\'' {
+						builder.WriteRune('\'')
+						pos++
+						continue
+					}
+					pos++
+					break
+				}
+				builder.WriteRune(input[pos])
+			}
+			tokens = append(tokens, token{kind: tokenString, text: builder.String(), pos: start})
+		default:
+			start := pos
+			for pos < len(input) && !unicode.IsSpace(input[pos]) && !strings.ContainsRune("(),'", input[pos]) {
+				pos++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(input[start:pos]), pos: start})
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, text: "end of expression", pos: len(input)}), nil
+}
+
+func (parser *filterParser) peek() token {
+	return parser.tokens[parser.pos]
+}
+
+func (parser *filterParser) next() token {
+	tok := parser.tokens[parser.pos]
+	if tok.kind != tokenEOF {
+		parser.pos++
+	}
+	return tok
+}
+
+// peekWord indica si el siguiente token es la palabra clave indicada
+func (parser *filterParser) peekWord(word string) bool {
+	tok := parser.peek()
+	return tok.kind == tokenWord && strings.EqualFold(tok.text, word)
+}
+
+func (parser *filterParser) expect(kind tokenKind, text string) (token, error) {
+	tok := parser.next()
+	if tok.kind != kind {
+		return tok, syntaxError(tok.pos, "expected '%s'", text)
+	}
+	return tok, nil
+}
+
+func (parser *filterParser) parseOr() (*filterExpr, error) {
+	return parser.parseList("or", parser.parseAnd)
+}
+
+func (parser *filterParser) parseAnd() (*filterExpr, error) {
+	return parser.parseList("and", parser.parseUnary)
+}
+
+func (parser *filterParser) parseList(keyword string, parse func() (*filterExpr, error)) (*filterExpr, error) {
+	expr, err := parse()
+	if err != nil {
+		return nil, err
+	}
+
+	children := []*filterExpr{expr}
+	for parser.peekWord(keyword) {
+		parser.next()
+		expr, err := parse()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, expr)
+	}
+
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	return &filterExpr{kind: keyword, children: children}, nil
+}
+
+func (parser *filterParser) parseUnary() (*filterExpr, error) {
+	if !parser.peekWord("not") {
+		return parser.parsePrimary()
+	}
+
+	pos := parser.next().pos
+	expr, err := parser.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return &filterExpr{kind: "not", children: []*filterExpr{expr}, pos: pos}, nil
+}
+
+func (parser *filterParser) parsePrimary() (*filterExpr, error) {
+	tok := parser.peek()
+	if tok.kind == tokenOpen {
+		parser.next()
+		expr, err := parser.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := parser.expect(tokenClose, ")"); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	}
+
+	if _, isFunction := likeFunctions[strings.ToLower(tok.text)]; tok.kind == tokenWord && isFunction {
+		return parser.parseFunction()
+	}
+
+	left, err := parser.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	oprToken := parser.next()
+	opr := strings.ToLower(oprToken.text)
+	if oprToken.kind == tokenWord && opr == "in" {
+		return parser.parseIn(left, tok.pos)
+	}
+	if _, exists := comparisonOperators[opr]; oprToken.kind != tokenWord || !exists {
+		return nil, syntaxError(oprToken.pos, "expected comparison operator, got '%s'", oprToken.text)
+	}
+
+	right, err := parser.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return &filterExpr{kind: "compare", left: left, right: right, opr: opr, pos: tok.pos}, nil
+}
+
+// parseFunction lee contains(prop,'texto'), opcionalmente seguida de "eq true" o "eq false"
+func (parser *filterParser) parseFunction() (*filterExpr, error) {
+	nameToken := parser.next()
+	expr := &filterExpr{kind: "function", opr: strings.ToLower(nameToken.text), pos: nameToken.pos}
+
+	if _, err := parser.expect(tokenOpen, "("); err != nil {
+		return nil, err
+	}
+
+	propertyToken := parser.next()
+	if propertyToken.kind == tokenWord && (strings.EqualFold(propertyToken.text, "tolower") || strings.EqualFold(propertyToken.text, "toupper")) {
+		expr.ilike = true
+		if _, err := parser.expect(tokenOpen, "("); err != nil {
+			return nil, err
+		}
+		propertyToken = parser.next()
+		if err := parser.checkProperty(propertyToken); err != nil {
+			return nil, err
+		}
+		if _, err := parser.expect(tokenClose, ")"); err != nil {
+			return nil, err
+		}
+	} else if err := parser.checkProperty(propertyToken); err != nil {
+		return nil, err
+	}
+	expr.left = operand{property: true, text: propertyToken.text}
+
+	if _, err := parser.expect(tokenComma, ","); err != nil {
+		return nil, err
+	}
+	valueToken, err := parser.expect(tokenString, "string")
+	if err != nil {
+		return nil, err
+	}
+	expr.right = operand{text: valueToken.text}
+	if _, err := parser.expect(tokenClose, ")"); err != nil {
+		return nil, err
+	}
+
+	// contains(...) eq false equivale a not contains(...)
+	if parser.peekWord("eq") || parser.peekWord("ne") {
+		negate := strings.EqualFold(parser.next().text, "ne")
+		boolToken := parser.next()
+		switch strings.ToLower(boolToken.text) {
+		case "true":
+		case "false":
+			negate = !negate
+		default:
+			return nil, syntaxError(boolToken.pos, "expected true or false")
+		}
+		if negate {
+			return &filterExpr{kind: "not", children: []*filterExpr{expr}, pos: expr.pos}, nil
+		}
+	}
+	return expr, nil
+}
+
+func (parser *filterParser) parseIn(left operand, pos int) (*filterExpr, error) {
+	if !left.property {
+		return nil, syntaxError(pos, "expected property before 'in'")
+	}
+	if _, err := parser.expect(tokenOpen, "("); err != nil {
+		return nil, err
+	}
+
+	var values []string
+	for {
+		value, err := parser.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if value.property || value.null {
+			return nil, syntaxError(parser.tokens[parser.pos-1].pos, "expected literal")
+		}
+		values = append(values, value.text)
+
+		tok := parser.next()
+		if tok.kind == tokenClose {
+			return &filterExpr{kind: "in", left: left, values: values, pos: pos}, nil
+		}
+		if tok.kind != tokenComma {
+			return nil, syntaxError(tok.pos, "expected ',' or ')'")
+		}
+	}
+}
+
+func (parser *filterParser) parseOperand() (operand, error) {
+	tok := parser.next()
+	switch tok.kind {
+	case tokenString:
+		return operand{text: tok.text}, nil
+	case tokenWord:
+		lower := strings.ToLower(tok.text)
+		switch {
+		case lower == "null":
+			return operand{null: true}, nil
+		case lower == "true" || lower == "false":
+			return operand{text: lower}, nil
+		case propertyRegexp.MatchString(tok.text):
+			return operand{property: true, text: tok.text}, nil
+		default:
+			// Números, fechas, fechas con hora y GUIDs se escriben sin comillas
+			return operand{text: tok.text}, nil
+		}
+	default:
+		return operand{}, syntaxError(tok.pos, "expected property or literal, got '%s'", tok.text)
+	}
+}
+
+func (parser *filterParser) checkProperty(tok token) error {
+	if tok.kind != tokenWord || !propertyRegexp.MatchString(tok.text) {
+		return syntaxError(tok.pos, "expected property, got '%s'", tok.text)
+	}
+	return nil
+}
+
+// compile convierte el árbol en filtros. "not" se compila como un grupo negado y no negando
+// los operadores: en OData una comparación con null es falsa y su negación verdadera, mientras
+// que en SQL ambas descartarían las filas con NULL.
+func compile(expr *filterExpr) (request.FilterNode, error) {
+	switch expr.kind {
+	case "and", "or":
+		node := request.FilterNode{Conn: strings.ToUpper(expr.kind)}
+		for _, child := range expr.children {
+			compiled, err := compile(child)
+			if err != nil {
+				return request.FilterNode{}, err
+			}
+			node.Children = append(node.Children, compiled)
+		}
+		return node, nil
+	case "not":
+		node, err := compile(expr.children[0])
+		if err != nil {
+			return request.FilterNode{}, err
+		}
+		// "eq null" y "ne null" nunca son desconocidos, se niegan con el filtro opuesto
+		if node.Filter != nil && !node.Negate {
+			switch node.Filter.Type {
+			case "NULL":
+				node.Filter.Type = "NOT_NULL"
+				return node, nil
+			case "NOT_NULL":
+				node.Filter.Type = "NULL"
+				return node, nil
+			}
+		}
+		node.Negate = !node.Negate
+		return node, nil
+	case "in":
+		return request.FilterNode{Filter: &request.FilterRequest{Type: "IN", Attr: expr.left.text, Vals: expr.values}}, nil
+	case "function":
+		filter := request.FilterRequest{Type: "SIMPLE", Attr: expr.left.text, Opr: "LIKE", Val: fmt.Sprintf(likeFunctions[expr.opr], request.EscapeLike(expr.right.text))}
+		if expr.ilike {
+			filter.Opr = "ILIKE"
+		}
+		return request.FilterNode{Filter: &filter}, nil
+	default:
+		return compileComparison(expr)
+	}
+}
+
+func compileComparison(expr *filterExpr) (request.FilterNode, error) {
+	left, right, opr := expr.left, expr.right, expr.opr
+	if !left.property {
+		left, right, opr = right, left, flippedOperators[opr]
+	}
+	if !left.property {
+		return request.FilterNode{}, syntaxError(expr.pos, "comparison needs a property")
+	}
+
+	var filter request.FilterRequest
+	switch {
+	case right.null && opr == "eq":
+		filter = request.FilterRequest{Type: "NULL", Attr: left.text}
+	case right.null && opr == "ne":
+		filter = request.FilterRequest{Type: "NOT_NULL", Attr: left.text}
+	case right.null:
+		return request.FilterNode{}, syntaxError(expr.pos, "null can only be compared with eq or ne")
+	case right.property:
+		filter = request.FilterRequest{Type: "COLUMN", Attr: left.text, Opr: comparisonOperators[opr], Val: right.text}
+	default:
+		filter = request.FilterRequest{Type: "SIMPLE", Attr: left.text, Opr: comparisonOperators[opr], Val: right.text}
+	}
+	return request.FilterNode{Filter: &filter}, nil
+}
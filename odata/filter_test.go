@@ -0,0 +1,140 @@
+package odata_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/devsstudio/gosql/odata"
+	"github.com/devsstudio/gosql/request"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		expected   []request.FilterRequest
+	}{
+		{
+			name:       "Comparisons joined with and",
+			expression: "status eq 'A' and score ge 10 and created lt 2024-01-01T00:00:00Z",
+			expected: []request.FilterRequest{
+				{Type: "SIMPLE", Attr: "status", Opr: "=", Val: "A", Conn: "AND"},
+				{Type: "SIMPLE", Attr: "score", Opr: ">=", Val: "10", Conn: "AND"},
+				{Type: "SIMPLE", Attr: "created", Opr: "<", Val: "2024-01-01T00:00:00Z", Conn: "AND"},
+			},
+		},
+		{
+			name:       "Or has lower precedence",
+			expression: "status eq 'A' or status eq 'B' and score gt 5",
+			expected: []request.FilterRequest{{Type: "GROUP", Conn: "AND", Filters: []request.FilterRequest{
+				{Type: "SIMPLE", Attr: "status", Opr: "=", Val: "A", Conn: "OR"},
+				{Type: "GROUP", Conn: "OR", Filters: []request.FilterRequest{
+					{Type: "SIMPLE", Attr: "status", Opr: "=", Val: "B", Conn: "AND"},
+					{Type: "SIMPLE", Attr: "score", Opr: ">", Val: "5", Conn: "AND"},
+				}},
+			}}},
+		},
+		{
+			name:       "Not is a negated group",
+			expression: "not (status eq 'A' or score gt 5) and not (email eq null) and not contains(name,'x') and not not id eq 1",
+			expected: []request.FilterRequest{
+				{Type: "NOT_GROUP", Conn: "AND", Filters: []request.FilterRequest{
+					{Type: "SIMPLE", Attr: "status", Opr: "=", Val: "A", Conn: "OR"},
+					{Type: "SIMPLE", Attr: "score", Opr: ">", Val: "5", Conn: "OR"},
+				}},
+				{Type: "NOT_NULL", Attr: "email", Conn: "AND"},
+				{Type: "NOT_GROUP", Conn: "AND", Filters: []request.FilterRequest{
+					{Type: "SIMPLE", Attr: "name", Opr: "LIKE", Val: "%x%", Conn: "AND"},
+				}},
+				{Type: "SIMPLE", Attr: "id", Opr: "=", Val: "1", Conn: "AND"},
+			},
+		},
+		{
+			name:       "Functions",
+			expression: "contains(name,'o''n') and startswith(tolower(email),'jo') and endswith(name,'x') eq true",
+			expected: []request.FilterRequest{
+				{Type: "SIMPLE", Attr: "name", Opr: "LIKE", Val: "%o'n%", Conn: "AND"},
+				{Type: "SIMPLE", Attr: "email", Opr: "ILIKE", Val: "jo%", Conn: "AND"},
+				{Type: "SIMPLE", Attr: "name", Opr: "LIKE", Val: "%x", Conn: "AND"},
+			},
+		},
+		{
+			name:       "Function arguments are matched literally",
+			expression: `contains(name,'50%') or startswith(name,'a_b') or endswith(name,'c\d')`,
+			expected: []request.FilterRequest{{Type: "GROUP", Conn: "AND", Filters: []request.FilterRequest{
+				{Type: "SIMPLE", Attr: "name", Opr: "LIKE", Val: `%50\%%`, Conn: "OR"},
+				{Type: "SIMPLE", Attr: "name", Opr: "LIKE", Val: `a\_b%`, Conn: "OR"},
+				{Type: "SIMPLE", Attr: "name", Opr: "LIKE", Val: `%c\\d`, Conn: "OR"},
+			}}},
+		},
+		{
+			name:       "Literal on the left, columns and in",
+			expression: "10 lt score and score gt minScore and id in (1, 2,3) and not (status in ('A'))",
+			expected: []request.FilterRequest{
+				{Type: "SIMPLE", Attr: "score", Opr: ">", Val: "10", Conn: "AND"},
+				{Type: "COLUMN", Attr: "score", Opr: ">", Val: "minScore", Conn: "AND"},
+				{Type: "IN", Attr: "id", Vals: []string{"1", "2", "3"}, Conn: "AND"},
+				{Type: "NOT_GROUP", Conn: "AND", Filters: []request.FilterRequest{
+					{Type: "IN", Attr: "status", Vals: []string{"A"}, Conn: "AND"},
+				}},
+			},
+		},
+		{
+			name:       "Function compared with false",
+			expression: "contains(name,'x') eq false",
+			expected: []request.FilterRequest{{Type: "NOT_GROUP", Conn: "AND", Filters: []request.FilterRequest{
+				{Type: "SIMPLE", Attr: "name", Opr: "LIKE", Val: "%x%", Conn: "AND"},
+			}}},
+		},
+		{
+			name:       "Booleans and null",
+			expression: "active eq true and email eq null",
+			expected: []request.FilterRequest{
+				{Type: "SIMPLE", Attr: "active", Opr: "=", Val: "true", Conn: "AND"},
+				{Type: "NULL", Attr: "email", Conn: "AND"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters, err := odata.ParseFilter(tt.expression)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, filters)
+		})
+	}
+}
+
+func TestParseFilter_Errors(t *testing.T) {
+	tests := []struct {
+		expression string
+		position   int
+		message    string
+	}{
+		{"status eq", 10, "expected property or literal, got 'end of expression'"},
+		{"status is 'A'", 8, "expected comparison operator, got 'is'"},
+		{"status eq 'A' status", 15, "unexpected token 'status'"},
+		{"(status eq 'A'", 15, "expected ')'"},
+		{"name eq 'John", 9, "unterminated string"},
+		{"1 eq 2", 1, "comparison needs a property"},
+		{"score gt null", 1, "null can only be compared with eq or ne"},
+		{"contains('x',name)", 10, "expected property, got 'x'"},
+		{"id in (1,name)", 10, "expected literal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			filters, err := odata.ParseFilter(tt.expression)
+
+			assert.Nil(t, filters)
+			var syntaxError *odata.SyntaxError
+			if assert.True(t, errors.As(err, &syntaxError)) {
+				assert.Equal(t, "$filter", syntaxError.Option)
+				assert.Equal(t, tt.position, syntaxError.Position)
+				assert.Equal(t, tt.message, syntaxError.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package odata
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/devsstudio/gosql/constants"
+	httphandler "github.com/devsstudio/gosql/handler"
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/response"
+	"github.com/devsstudio/gosql/services"
+	"github.com/devsstudio/gosql/types"
+)
+
+// Query son las opciones de consulta de sistema de OData ya interpretadas
+type Query struct {
+	Filters []request.FilterRequest // $filter
+	Order   types.Order             // $orderby
+	Top     *int                    // $top, nil sin límite
+	Skip    int                     // $skip
+	Count   bool                    // $count
+	Select  []string                // $select, vacío devuelve todas las columnas
+}
+
+// Options configura el adaptador
+type Options struct {
+	MaxPageSize int                              // Filas por página, 0 usa constants.ODATA_MAX_PAGE_SIZE
+	Context     string                           // Valor de @odata.context, p.e. "https://host/odata/$metadata#Users"
+	OnError     func(r *http.Request, err error) // Recibe los errores de la base de datos, que el cliente recibe con el código InternalServerError
+}
+
+// $format solo admite JSON, se acepta para los clientes que lo envían siempre
+var supportedOptions = map[string]struct{}{
+	"$filter": {}, "$orderby": {}, "$top": {}, "$skip": {}, "$count": {}, "$select": {}, "$format": {},
+}
+
+// ParseQuery interpreta las opciones de consulta de una URL OData v4. Las opciones de sistema
+// no soportadas ($expand, $search, ...) son un error, los demás parámetros se ignoran.
+func ParseQuery(values url.Values) (*Query, error) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	query := &Query{Filters: []request.FilterRequest{}}
+	for _, key := range keys {
+		if !strings.HasPrefix(key, "$") {
+			continue
+		}
+		if _, supported := supportedOptions[key]; !supported {
+			return nil, &SyntaxError{Option: key, Message: "query option is not supported"}
+		}
+
+		value := values.Get(key)
+		switch key {
+		case "$filter":
+			filters, err := ParseFilter(value)
+			if err != nil {
+				return nil, err
+			}
+			query.Filters = filters
+		case "$orderby":
+			order, err := parseOrderBy(value)
+			if err != nil {
+				return nil, err
+			}
+			query.Order = order
+		case "$top":
+			top, err := parseNonNegative(key, value)
+			if err != nil {
+				return nil, err
+			}
+			query.Top = &top
+		case "$skip":
+			skip, err := parseNonNegative(key, value)
+			if err != nil {
+				return nil, err
+			}
+			query.Skip = skip
+		case "$count":
+			count, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, &SyntaxError{Option: key, Message: "must be true or false"}
+			}
+			query.Count = count
+		case "$select":
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					query.Select = append(query.Select, item)
+				}
+			}
+		case "$format":
+			if format := strings.ToLower(value); format != "json" && !strings.HasPrefix(format, "application/json") {
+				return nil, &SyntaxError{Option: key, Message: "only json is supported"}
+			}
+		}
+	}
+	return query, nil
+}
+
+// parseOrderBy interpreta "name desc, id" como claves de ordenamiento
+func parseOrderBy(value string) (types.Order, error) {
+	var order types.Order
+	for _, item := range strings.Split(value, ",") {
+		fields := strings.Fields(item)
+		if len(fields) == 0 || len(fields) > 2 {
+			return nil, &SyntaxError{Option: "$orderby", Message: "invalid item '" + strings.TrimSpace(item) + "'"}
+		}
+
+		direction := "asc"
+		if len(fields) == 2 {
+			direction = strings.ToLower(fields[1])
+			if direction != "asc" && direction != "desc" {
+				return nil, &SyntaxError{Option: "$orderby", Message: "direction '" + fields[1] + "' not allowed"}
+			}
+		}
+		order = append(order, types.OrderBy{Column: fields[0], Direction: direction})
+	}
+	return order, nil
+}
+
+func parseNonNegative(option string, value string) (int, error) {
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 0 {
+		return 0, &SyntaxError{Option: option, Message: "must be a non-negative integer"}
+	}
+	return number, nil
+}
+
+// Find ejecuta la consulta sobre el servicio. Si quedan filas fuera de la página y requestURL
+// no es nil, @odata.nextLink apunta a requestURL con $skip y $top de la página siguiente.
+func Find(service *services.Pagination, query *Query, options Options, requestURL *url.URL) (*response.ODataResponse, error) {
+	exclusions, err := getExclusions(service, query.Select)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := options.MaxPageSize
+	if pageSize <= 0 {
+		pageSize = constants.ODATA_MAX_PAGE_SIZE
+	}
+	limit := pageSize
+	if query.Top != nil && *query.Top < limit {
+		limit = *query.Top
+	}
+
+	result := &response.ODataResponse{Context: options.Context, Value: []map[string]interface{}{}}
+
+	// Con $top=0 solo interesa el total
+	if limit > 0 {
+		findRequest := request.FindRequest{Offset: query.Skip, Limit: limit, Order: query.Order, Include: query.Select}
+		result.Value, err = service.FindAll(query.Filters, findRequest, &exclusions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// El total filtrado solo hace falta para $count o si la página se llenó y puede haber otra
+	count := query.Skip + len(result.Value)
+	if query.Count || (limit > 0 && len(result.Value) == limit) {
+		count, err = service.Count(query.Filters)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if query.Count {
+		result.Count = &count
+	}
+
+	remaining := count - query.Skip - len(result.Value)
+	if query.Top != nil && *query.Top-len(result.Value) < remaining {
+		remaining = *query.Top - len(result.Value)
+	}
+	if remaining > 0 && len(result.Value) > 0 && requestURL != nil {
+		result.NextLink = getNextLink(requestURL, query, len(result.Value))
+	}
+
+	return result, nil
+}
+
+// getExclusions valida $select y devuelve las columnas seleccionables que no se pidieron
+func getExclusions(service *services.Pagination, selected []string) ([]string, error) {
+	exclusions := []string{}
+	if len(selected) == 0 || len(selected) == 1 && selected[0] == "*" {
+		return exclusions, nil
+	}
+
+	selectedSet := make(map[string]struct{}, len(selected))
+	for _, column := range selected {
+		if def, exists := service.ColumnDef(column); !exists || !def.Selectable {
+			return nil, errors.New("attribute select '" + column + "' is not allowed")
+		}
+		selectedSet[column] = struct{}{}
+	}
+
+	for _, def := range service.ColumnDefs() {
+		if _, exists := selectedSet[def.Name]; def.Selectable && !exists {
+			exclusions = append(exclusions, def.Name)
+		}
+	}
+	return exclusions, nil
+}
+
+func getNextLink(requestURL *url.URL, query *Query, returned int) string {
+	next := *requestURL
+	values := next.Query()
+	values.Set("$skip", strconv.Itoa(query.Skip+returned))
+	if query.Top != nil {
+		values.Set("$top", strconv.Itoa(*query.Top-returned))
+	}
+	next.RawQuery = values.Encode()
+	return next.String()
+}
+
+type odataHandler struct {
+	service *services.Pagination
+	options Options
+}
+
+// New crea un http.Handler que responde la colección con el sobre JSON de OData v4.
+// Los errores de sintaxis y de validación responden 400 y los de la base de datos 500.
+func New(service *services.Pagination, options Options) http.Handler {
+	return &odataHandler{service: service, options: options}
+}
+
+func (handler *odataHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", "GET")
+		writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "method not allowed")
+		return
+	}
+
+	query, err := ParseQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+
+	result, err := Find(handler.service.WithContext(r.Context()), query, handler.options, getRequestURL(r))
+	if err != nil {
+		if httphandler.ErrorStatus(r, err, handler.options.OnError) == http.StatusInternalServerError {
+			writeError(w, http.StatusInternalServerError, "InternalServerError", "internal server error")
+			return
+		}
+		writeError(w, http.StatusBadRequest, "BadRequest", err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// getRequestURL arma la URL absoluta de la solicitud. Usa RequestURI para conservar
+// el prefijo que quita http.StripPrefix.
+func getRequestURL(r *http.Request) *url.URL {
+	requestURL, err := url.ParseRequestURI(r.RequestURI)
+	if err != nil {
+		copied := *r.URL
+		requestURL = &copied
+	}
+	requestURL.Scheme = "http"
+	if r.TLS != nil {
+		requestURL.Scheme = "https"
+	}
+	requestURL.Host = r.Host
+	return requestURL
+}
+
+func writeError(w http.ResponseWriter, status int, code string, message string) {
+	writeJSON(w, status, response.ODataErrorResponse{Error: response.ODataError{Code: code, Message: message}})
+}
+
+// writeJSON agrega los encabezados de OData v4 antes de escribir la respuesta
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json;odata.metadata=minimal")
+	w.Header().Set("OData-Version", "4.0")
+	httphandler.WriteJSON(w, status, body)
+}
@@ -0,0 +1,162 @@
+package odata_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/devsstudio/gosql/internal/testdb"
+	"github.com/devsstudio/gosql/odata"
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/services"
+	"github.com/devsstudio/gosql/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newService(t *testing.T, table string) *services.Pagination {
+	statements := []string{
+		`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, status TEXT, score INTEGER, secret TEXT)`,
+		`INSERT INTO users VALUES (1, 'John', 'A', 10, 'x'), (2, 'Jane', 'A', 20, 'y'), (3, 'Bob', 'I', 30, 'z'), (4, 'Alice', 'A', NULL, 'w')`,
+	}
+
+	secret := types.NewColumnDef("secret", "secret", types.ColumnTypeText)
+	secret.Hidden = true
+
+	return testdb.NewService(t, statements, types.ListParams{
+		Table: table,
+		ColumnDefs: []types.ColumnDef{
+			types.NewColumnDef("id", "id", types.ColumnTypeInt),
+			types.NewColumnDef("name", "name", types.ColumnTypeText),
+			types.NewColumnDef("status", "status", types.ColumnTypeText),
+			types.NewColumnDef("score", "score", types.ColumnTypeInt),
+			secret,
+		},
+	})
+}
+
+func TestParseQuery(t *testing.T) {
+	values := url.Values{
+		"$filter":  {"status eq 'A'"},
+		"$orderby": {"score desc, id"},
+		"$top":     {"5"},
+		"$skip":    {"10"},
+		"$count":   {"true"},
+		"$select":  {"id, name"},
+		"$format":  {"json"},
+		"other":    {"ignored"},
+	}
+
+	query, err := odata.ParseQuery(values)
+
+	assert.NoError(t, err)
+	top := 5
+	assert.Equal(t, &odata.Query{
+		Filters: []request.FilterRequest{{Type: "SIMPLE", Attr: "status", Opr: "=", Val: "A", Conn: "AND"}},
+		Order:   types.Order{{Column: "score", Direction: "desc"}, {Column: "id", Direction: "asc"}},
+		Top:     &top,
+		Skip:    10,
+		Count:   true,
+		Select:  []string{"id", "name"},
+	}, query)
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	tests := []struct {
+		values   url.Values
+		expected string
+	}{
+		{url.Values{"$expand": {"orders"}}, "$expand: query option is not supported"},
+		{url.Values{"$top": {"-1"}}, "$top: must be a non-negative integer"},
+		{url.Values{"$skip": {"x"}}, "$skip: must be a non-negative integer"},
+		{url.Values{"$count": {"yes"}}, "$count: must be true or false"},
+		{url.Values{"$orderby": {"name up"}}, "$orderby: direction 'up' not allowed"},
+		{url.Values{"$orderby": {"name,"}}, "$orderby: invalid item ''"},
+		{url.Values{"$format": {"xml"}}, "$format: only json is supported"},
+		{url.Values{"$filter": {"name eq"}}, "$filter: expected property or literal, got 'end of expression' at position 8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			query, err := odata.ParseQuery(tt.values)
+
+			assert.Nil(t, query)
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func serve(h http.Handler, method string, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+	return w
+}
+
+func TestHandler(t *testing.T) {
+	h := odata.New(newService(t, "users"), odata.Options{MaxPageSize: 2, Context: "$metadata#Users"})
+
+	w := serve(h, http.MethodGet, "/odata/Users?$filter="+url.QueryEscape("status eq 'A' and score ne null")+"&$orderby=score%20desc&$count=true&$select=id,name")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json;odata.metadata=minimal", w.Header().Get("Content-Type"))
+	assert.Equal(t, "4.0", w.Header().Get("OData-Version"))
+	assert.JSONEq(t, `{
+		"@odata.context": "$metadata#Users",
+		"@odata.count": 2,
+		"value": [{"id": 2, "name": "Jane"}, {"id": 1, "name": "John"}]
+	}`, w.Body.String())
+
+	// Paginación del servidor: 4 filas en páginas de 2
+	w = serve(h, http.MethodGet, "/odata/Users?$orderby=id&$select=id")
+	assert.JSONEq(t, `{
+		"@odata.context": "$metadata#Users",
+		"value": [{"id": 1}, {"id": 2}],
+		"@odata.nextLink": "http://example.com/odata/Users?%24orderby=id&%24select=id&%24skip=2"
+	}`, w.Body.String())
+
+	// $top limita el total aunque haya más filas
+	w = serve(h, http.MethodGet, "/odata/Users?$orderby=id&$select=id&$top=3&$skip=1")
+	assert.JSONEq(t, `{
+		"@odata.context": "$metadata#Users",
+		"value": [{"id": 2}, {"id": 3}],
+		"@odata.nextLink": "http://example.com/odata/Users?%24orderby=id&%24select=id&%24skip=3&%24top=1"
+	}`, w.Body.String())
+
+	w = serve(h, http.MethodGet, "/odata/Users?$orderby=id&$select=id&$top=1&$skip=1")
+	assert.JSONEq(t, `{"@odata.context": "$metadata#Users", "value": [{"id": 2}]}`, w.Body.String())
+
+	// not incluye las filas con NULL, como en OData
+	w = serve(h, http.MethodGet, "/odata/Users?$filter="+url.QueryEscape("not (score gt 15)")+"&$orderby=id&$select=id&$count=true")
+	assert.JSONEq(t, `{"@odata.context": "$metadata#Users", "@odata.count": 2, "value": [{"id": 1}, {"id": 4}]}`, w.Body.String())
+
+	// $top=0 solo cuenta, las columnas ocultas se devuelven si se seleccionan
+	w = serve(h, http.MethodGet, "/odata/Users?$top=0&$count=true")
+	assert.JSONEq(t, `{"@odata.context": "$metadata#Users", "@odata.count": 4, "value": []}`, w.Body.String())
+
+	w = serve(h, http.MethodGet, "/odata/Users?$filter=id%20eq%203&$select=secret")
+	assert.JSONEq(t, `{"@odata.context": "$metadata#Users", "value": [{"secret": "z"}]}`, w.Body.String())
+}
+
+func TestHandler_Errors(t *testing.T) {
+	h := odata.New(newService(t, "users"), odata.Options{})
+
+	w := serve(h, http.MethodGet, "/?$filter=unknown%20eq%201")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error": {"code": "BadRequest", "message": "attribute filter 'unknown' is not allowed"}}`, w.Body.String())
+
+	w = serve(h, http.MethodGet, "/?$select=password")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error": {"code": "BadRequest", "message": "attribute select 'password' is not allowed"}}`, w.Body.String())
+
+	w = serve(h, http.MethodGet, "/?$orderby=name%20up")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serve(h, http.MethodPost, "/")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	var logged error
+	h = odata.New(newService(t, "missing"), odata.Options{OnError: func(r *http.Request, err error) { logged = err }})
+	w = serve(h, http.MethodGet, "/")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"error": {"code": "InternalServerError", "message": "internal server error"}}`, w.Body.String())
+	assert.ErrorContains(t, logged, "no such table")
+}
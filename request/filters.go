@@ -0,0 +1,58 @@
+package request
+
+import "strings"
+
+// likeEscaper antepone "\" a los caracteres especiales de un patrón LIKE
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// FilterNode es el árbol que arman los parsers de expresiones (RSQL, OData): una hoja con
+// Filter o la conjunción/disyunción (Conn "AND" u "OR") de sus Children. Negate niega el nodo.
+type FilterNode struct {
+	Filter   *FilterRequest
+	Conn     string
+	Children []FilterNode
+	Negate   bool
+}
+
+// Filters convierte el árbol en los filtros que entiende el servicio. Una conjunción en la
+// raíz se devuelve como filtros de primer nivel, el resto de los nodos como filtros GROUP y
+// los nodos negados como filtros NOT_GROUP.
+func (node FilterNode) Filters() []FilterRequest {
+	if node.Filter == nil && node.Conn == "AND" && !node.Negate {
+		filters := make([]FilterRequest, len(node.Children))
+		for i, child := range node.Children {
+			filters[i] = child.toFilter("AND")
+		}
+		return filters
+	}
+	return []FilterRequest{node.toFilter("AND")}
+}
+
+func (node FilterNode) toFilter(conn string) FilterRequest {
+	if node.Filter != nil && !node.Negate {
+		filter := *node.Filter
+		filter.Conn = conn
+		return filter
+	}
+
+	groupType, children, childConn := "GROUP", node.Children, node.Conn
+	if node.Negate {
+		groupType = "NOT_GROUP"
+	}
+	// Una hoja negada es un grupo con un solo filtro
+	if node.Filter != nil {
+		children, childConn = []FilterNode{{Filter: node.Filter}}, "AND"
+	}
+
+	filters := make([]FilterRequest, len(children))
+	for i, child := range children {
+		filters[i] = child.toFilter(childConn)
+	}
+	return FilterRequest{Type: groupType, Conn: conn, Filters: filters}
+}
+
+// EscapeLike escapa "\", "%" y "_" para que el texto se compare literalmente dentro de un
+// patrón LIKE. El servicio usa "\" como carácter de escape en todos los motores.
+func EscapeLike(text string) string {
+	return likeEscaper.Replace(text)
+}
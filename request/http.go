@@ -0,0 +1,496 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/devsstudio/gosql/types"
+	"github.com/go-playground/validator/v10"
+)
+
+// ListRequest reúne los filtros y la paginación de una solicitud HTTP
+type ListRequest struct {
+	Filters []FilterRequest `json:"filters" validate:"omitempty,dive"`
+	PaginationRequest
+	Offset int    `json:"offset" validate:"omitempty,min=0"`
+	Cursor string `json:"cursor" validate:"omitempty"`
+}
+
+// ValidationError agrupa los errores de los parámetros de una solicitud
+type ValidationError struct {
+	Errors []types.FieldError `json:"errors"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fieldError := range e.Errors {
+		messages[i] = fieldError.Field + " " + fieldError.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Operadores de la forma compacta filter=attr:op:valor
+var compactOperators = map[string]FilterRequest{
+	"eq":          {Type: "SIMPLE", Opr: "="},
+	"ne":          {Type: "SIMPLE", Opr: "<>"},
+	"gt":          {Type: "SIMPLE", Opr: ">"},
+	"gte":         {Type: "SIMPLE", Opr: ">="},
+	"lt":          {Type: "SIMPLE", Opr: "<"},
+	"lte":         {Type: "SIMPLE", Opr: "<="},
+	"like":        {Type: "SIMPLE", Opr: "LIKE"},
+	"ilike":       {Type: "SIMPLE", Opr: "ILIKE"},
+	"in":          {Type: "IN"},
+	"nin":         {Type: "NOT_IN"},
+	"between":     {Type: "BETWEEN"},
+	"nbetween":    {Type: "NOT_BETWEEN"},
+	"null":        {Type: "NULL"},
+	"notnull":     {Type: "NOT_NULL"},
+	"date":        {Type: "DATE"},
+	"datebetween": {Type: "DATE_BETWEEN"},
+}
+
+// FromHTTPRequest arma un ListRequest a partir del cuerpo JSON (si lo hay) y de la query string.
+// Los parámetros de la query string se aplican sobre los del cuerpo. Acepta:
+//
+//	page=2&limit=20&count=true&offset=40&cursor=...
+//	order[name]=asc&order[id]=desc    o    order=name:asc,id:desc
+//	include=a,b    include[]=a    aggregates[amount]=sum
+//	filters[0][attr]=status&filters[0][val]=A&filters[0][vals][]=x&filters[0][filters][0][attr]=...
+//	filter=status:eq:A&filter=id:in:1,2,3&filter=email:null
+//
+// Los errores de formato y de validación se devuelven juntos en un *ValidationError.
+func FromHTTPRequest(r *http.Request) (*ListRequest, error) {
+	listRequest := &ListRequest{}
+	var fieldErrors []types.FieldError
+
+	if err := decodeJSONBody(r, listRequest); err != nil {
+		return nil, err
+	}
+
+	pairs, err := parseQueryPairs(r.URL.RawQuery)
+	if err != nil {
+		return nil, &ValidationError{Errors: []types.FieldError{{Field: "query", Message: "is not a valid query string"}}}
+	}
+
+	filters := newQueryNode()
+	for _, pair := range pairs {
+		path := splitQueryKey(pair.key)
+		switch path[0] {
+		case "page":
+			fieldErrors = appendIntParam(fieldErrors, "page", pair.value, &listRequest.Page)
+		case "limit":
+			fieldErrors = appendIntParam(fieldErrors, "limit", pair.value, &listRequest.Limit)
+		case "offset":
+			fieldErrors = appendIntParam(fieldErrors, "offset", pair.value, &listRequest.Offset)
+		case "count":
+			count, err := strconv.ParseBool(pair.value)
+			if err != nil {
+				fieldErrors = append(fieldErrors, types.FieldError{Field: "count", Message: "must be a boolean"})
+			}
+			listRequest.Count = count
+		case "cursor":
+			listRequest.Cursor = pair.value
+		case "include":
+			listRequest.Include = append(listRequest.Include, splitList(pair.value)...)
+		case "order":
+			if len(path) > 1 {
+				listRequest.Order = setOrder(listRequest.Order, path[1], pair.value)
+				continue
+			}
+			for _, item := range splitList(pair.value) {
+				column, direction, _ := strings.Cut(item, ":")
+				listRequest.Order = setOrder(listRequest.Order, column, direction)
+			}
+		case "aggregates":
+			if len(path) != 2 {
+				fieldErrors = append(fieldErrors, types.FieldError{Field: "aggregates", Message: "must use the form aggregates[column]=function"})
+				continue
+			}
+			if listRequest.Aggregates == nil {
+				listRequest.Aggregates = map[string]string{}
+			}
+			listRequest.Aggregates[path[1]] = pair.value
+		case "filters":
+			filters.set(path[1:], pair.value)
+		case "filter":
+			filter, err := parseCompactFilter(pair.value)
+			if err != nil {
+				fieldErrors = append(fieldErrors, types.FieldError{Field: "filter", Message: err.Error()})
+				continue
+			}
+			listRequest.Filters = append(listRequest.Filters, filter)
+		}
+	}
+
+	bracketFilters, errs := filters.toFilters("filters")
+	fieldErrors = append(fieldErrors, errs...)
+	listRequest.Filters = append(listRequest.Filters, bracketFilters...)
+	// Los servicios esperan un arreglo aunque no haya filtros
+	if listRequest.Filters == nil {
+		listRequest.Filters = []FilterRequest{}
+	}
+	normalizeFilters(listRequest.Filters)
+	// Las funciones de agregación llegan en cualquier caso, tanto en la query como en el cuerpo JSON
+	for column, function := range listRequest.Aggregates {
+		listRequest.Aggregates[column] = strings.ToLower(function)
+	}
+
+	if len(fieldErrors) > 0 {
+		return nil, &ValidationError{Errors: fieldErrors}
+	}
+
+	if err := Validate(listRequest); err != nil {
+		return nil, err
+	}
+	return listRequest, nil
+}
+
+// Validate aplica las etiquetas validate de la solicitud y devuelve los errores por campo
+func Validate(value any) error {
+	err := newValidator().Struct(value)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	fieldErrors := make([]types.FieldError, 0, len(validationErrors))
+	for _, fieldError := range validationErrors {
+		fieldErrors = append(fieldErrors, types.FieldError{
+			Field:   getFieldPath(fieldError.Namespace()),
+			Message: getValidationMessage(fieldError),
+		})
+	}
+	return &ValidationError{Errors: fieldErrors}
+}
+
+// PaginationOffset devuelve la solicitud de paginación por desplazamiento
+func (listRequest *ListRequest) PaginationOffset() PaginationOffsetRequest {
+	return PaginationOffsetRequest{
+		Offset:     listRequest.Offset,
+		Limit:      listRequest.Limit,
+		Order:      listRequest.Order,
+		Include:    listRequest.Include,
+		Aggregates: listRequest.Aggregates,
+	}
+}
+
+// Find devuelve la solicitud de FindAll
+func (listRequest *ListRequest) Find() FindRequest {
+	return FindRequest{
+		Limit:   listRequest.Limit,
+		Order:   listRequest.Order,
+		Include: listRequest.Include,
+	}
+}
+
+// CursorPage devuelve la solicitud de paginación por cursor
+func (listRequest *ListRequest) CursorPage() CursorRequest {
+	return CursorRequest{
+		Cursor:  listRequest.Cursor,
+		Limit:   listRequest.Limit,
+		Order:   listRequest.Order,
+		Include: listRequest.Include,
+	}
+}
+
+func decodeJSONBody(r *http.Request, listRequest *ListRequest) error {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		return nil
+	}
+
+	err := json.NewDecoder(r.Body).Decode(listRequest)
+	if err == nil || errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) {
+		return &ValidationError{Errors: []types.FieldError{{Field: typeError.Field, Message: "must be a " + typeError.Type.String()}}}
+	}
+	return &ValidationError{Errors: []types.FieldError{{Field: "body", Message: "is not valid json"}}}
+}
+
+type queryPair struct {
+	key   string
+	value string
+}
+
+// parseQueryPairs es url.ParseQuery conservando el orden de los parámetros, necesario para order[...]
+func parseQueryPairs(rawQuery string) ([]queryPair, error) {
+	var pairs []queryPair
+	for _, part := range strings.Split(rawQuery, "&") {
+		if part == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(part, "=")
+		key, err := url.QueryUnescape(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err = url.QueryUnescape(value)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, queryPair{key: key, value: value})
+	}
+	return pairs, nil
+}
+
+// splitQueryKey separa "filters[0][vals][]" en ["filters", "0", "vals", ""]
+func splitQueryKey(key string) []string {
+	name, rest, found := strings.Cut(key, "[")
+	path := []string{name}
+	if !found {
+		return path
+	}
+	for _, segment := range strings.Split(strings.TrimSuffix(rest, "]"), "][") {
+		path = append(path, segment)
+	}
+	return path
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func appendIntParam(fieldErrors []types.FieldError, field string, value string, target *int) []types.FieldError {
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return append(fieldErrors, types.FieldError{Field: field, Message: "must be an integer"})
+	}
+	*target = number
+	return fieldErrors
+}
+
+// setOrder agrega la clave o reemplaza su dirección si ya estaba
+func setOrder(order types.Order, column string, direction string) types.Order {
+	if direction == "" {
+		direction = "asc"
+	}
+	for i := range order {
+		if order[i].Column == column {
+			order[i].Direction = direction
+			return order
+		}
+	}
+	return append(order, types.OrderBy{Column: column, Direction: direction})
+}
+
+func parseCompactFilter(value string) (FilterRequest, error) {
+	parts := strings.SplitN(value, ":", 3)
+	if len(parts) < 2 || parts[0] == "" {
+		return FilterRequest{}, errors.New("must use the form attr:operator:value")
+	}
+
+	filter, exists := compactOperators[strings.ToLower(parts[1])]
+	if !exists {
+		return FilterRequest{}, fmt.Errorf("operator '%s' is not supported", parts[1])
+	}
+	filter.Attr = parts[0]
+
+	val := ""
+	if len(parts) == 3 {
+		val = parts[2]
+	}
+	switch filter.Type {
+	case "IN", "NOT_IN", "BETWEEN", "NOT_BETWEEN", "DATE_BETWEEN":
+		filter.Vals = splitList(val)
+	default:
+		filter.Val = val
+	}
+	return filter, nil
+}
+
+// normalizeFilters pasa a mayúsculas tipo, operador y conector como lo hace el servicio
+func normalizeFilters(filters []FilterRequest) {
+	for i := range filters {
+		filters[i].Type = strings.ToUpper(filters[i].Type)
+		filters[i].Opr = strings.ToUpper(filters[i].Opr)
+		filters[i].Conn = strings.ToUpper(filters[i].Conn)
+		normalizeFilters(filters[i].Filters)
+	}
+}
+
+// queryNode es un nivel de la sintaxis con corchetes: un valor o un conjunto de hijos
+type queryNode struct {
+	values   []string
+	children map[string]*queryNode
+	keys     []string // Claves de children en el orden recibido
+}
+
+func newQueryNode() *queryNode {
+	return &queryNode{children: map[string]*queryNode{}}
+}
+
+func (node *queryNode) set(path []string, value string) {
+	if len(path) == 0 {
+		node.values = append(node.values, value)
+		return
+	}
+
+	key := path[0]
+	// "vals[]" agrega un elemento nuevo
+	if key == "" {
+		key = strconv.Itoa(len(node.keys))
+	}
+	child, exists := node.children[key]
+	if !exists {
+		child = newQueryNode()
+		node.children[key] = child
+		node.keys = append(node.keys, key)
+	}
+	child.set(path[1:], value)
+}
+
+// list devuelve los hijos ordenados por índice numérico
+func (node *queryNode) list() ([]*queryNode, []string, bool) {
+	keys := append([]string{}, node.keys...)
+	for _, key := range keys {
+		if _, err := strconv.Atoi(key); err != nil {
+			return nil, nil, false
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		a, _ := strconv.Atoi(keys[i])
+		b, _ := strconv.Atoi(keys[j])
+		return a < b
+	})
+
+	nodes := make([]*queryNode, len(keys))
+	for i, key := range keys {
+		nodes[i] = node.children[key]
+	}
+	return nodes, keys, true
+}
+
+// strings devuelve los valores de una lista "vals[]=a&vals[]=b", "vals[0]=a" o "vals=a&vals=b"
+func (node *queryNode) strings() []string {
+	values := append([]string{}, node.values...)
+	if children, _, ok := node.list(); ok {
+		for _, child := range children {
+			values = append(values, child.values...)
+		}
+	}
+	return values
+}
+
+func (node *queryNode) value() string {
+	if len(node.values) == 0 {
+		return ""
+	}
+	return node.values[len(node.values)-1]
+}
+
+func (node *queryNode) toFilters(field string) ([]FilterRequest, []types.FieldError) {
+	if len(node.keys) == 0 {
+		return nil, nil
+	}
+	children, keys, ok := node.list()
+	if !ok {
+		return nil, []types.FieldError{{Field: field, Message: "must use numeric indexes"}}
+	}
+
+	var filters []FilterRequest
+	var fieldErrors []types.FieldError
+	for i, child := range children {
+		path := field + "[" + keys[i] + "]"
+		filter := FilterRequest{}
+		for _, name := range child.keys {
+			attribute := child.children[name]
+			switch name {
+			case "type":
+				filter.Type = attribute.value()
+			case "attr":
+				filter.Attr = attribute.value()
+			case "attrs":
+				filter.Attrs = attribute.strings()
+			case "val":
+				filter.Val = attribute.value()
+			case "vals":
+				filter.Vals = attribute.strings()
+			case "opr":
+				filter.Opr = attribute.value()
+			case "conn":
+				filter.Conn = attribute.value()
+			case "filters":
+				nested, errs := attribute.toFilters(path + ".filters")
+				filter.Filters = nested
+				fieldErrors = append(fieldErrors, errs...)
+			default:
+				fieldErrors = append(fieldErrors, types.FieldError{Field: path + "." + name, Message: "is not a filter attribute"})
+			}
+		}
+		filters = append(filters, filter)
+	}
+	return filters, fieldErrors
+}
+
+func newValidator() *validator.Validate {
+	validate := validator.New()
+	// Los errores usan los nombres JSON
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	return validate
+}
+
+// getFieldPath convierte "ListRequest.PaginationRequest.limit" en "limit" y "ListRequest.filters[0].opr" en "filters[0].opr"
+func getFieldPath(namespace string) string {
+	parts := strings.Split(namespace, ".")
+	var path []string
+	for i, part := range parts {
+		// Se omite la raíz y los structs embebidos, que no tienen nombre JSON
+		if i == 0 || part == "PaginationRequest" {
+			continue
+		}
+		path = append(path, part)
+	}
+	return strings.Join(path, ".")
+}
+
+func getValidationMessage(fieldError validator.FieldError) string {
+	switch fieldError.Tag() {
+	case "required":
+		return "is required"
+	case "oneof":
+		return "must be one of: " + fieldError.Param()
+	case "gte", "min":
+		return "must be greater than or equal to " + fieldError.Param()
+	case "lte", "max":
+		return "must be less than or equal to " + fieldError.Param()
+	case "boolean":
+		return "must be a boolean"
+	default:
+		return "failed on the '" + fieldError.Tag() + "' validation"
+	}
+}
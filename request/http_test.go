@@ -0,0 +1,178 @@
+package request_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromHTTPRequest_BracketSyntax(t *testing.T) {
+	query := "page=2&limit=20&count=true&order[name]=asc&order[id]=desc&include[]=email&aggregates[amount]=SUM" +
+		"&filters[0][attr]=status&filters[0][val]=A" +
+		"&filters[1][type]=in&filters[1][attr]=id&filters[1][vals][]=1&filters[1][vals][]=2" +
+		"&filters[2][type]=GROUP&filters[2][conn]=or&filters[2][filters][0][attr]=name&filters[2][filters][0][opr]=like&filters[2][filters][0][val]=%25jo%25" +
+		"&filters[2][filters][1][attr]=email&filters[2][filters][1][opr]=ilike&filters[2][filters][1][val]=%25jo%25"
+	r := httptest.NewRequest(http.MethodGet, "/users?"+query, nil)
+
+	listRequest, err := request.FromHTTPRequest(r)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, listRequest.Page)
+	assert.Equal(t, 20, listRequest.Limit)
+	assert.True(t, listRequest.Count)
+	assert.Equal(t, types.Order{{Column: "name", Direction: "asc"}, {Column: "id", Direction: "desc"}}, listRequest.Order)
+	assert.Equal(t, []string{"email"}, listRequest.Include)
+	assert.Equal(t, map[string]string{"amount": "sum"}, listRequest.Aggregates)
+	assert.Equal(t, []request.FilterRequest{
+		{Attr: "status", Val: "A"},
+		{Type: "IN", Attr: "id", Vals: []string{"1", "2"}},
+		{Type: "GROUP", Conn: "OR", Filters: []request.FilterRequest{
+			{Attr: "name", Opr: "LIKE", Val: "%jo%"},
+			{Attr: "email", Opr: "ILIKE", Val: "%jo%"},
+		}},
+	}, listRequest.Filters)
+}
+
+func TestFromHTTPRequest_IndexOrder(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/users?filters[10][attr]=b&filters[2][attr]=a&filters[2][vals][1]=y&filters[2][vals][0]=x", nil)
+
+	listRequest, err := request.FromHTTPRequest(r)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []request.FilterRequest{
+		{Attr: "a", Vals: []string{"x", "y"}},
+		{Attr: "b"},
+	}, listRequest.Filters)
+}
+
+func TestFromHTTPRequest_CompactSyntax(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/users?filter=status:eq:A&filter=id:in:1,2,3&filter=email:null&filter=url:eq:http://x&order=name:desc,id&limit=5", nil)
+
+	listRequest, err := request.FromHTTPRequest(r)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []request.FilterRequest{
+		{Type: "SIMPLE", Attr: "status", Opr: "=", Val: "A"},
+		{Type: "IN", Attr: "id", Vals: []string{"1", "2", "3"}},
+		{Type: "NULL", Attr: "email"},
+		{Type: "SIMPLE", Attr: "url", Opr: "=", Val: "http://x"},
+	}, listRequest.Filters)
+	assert.Equal(t, types.Order{{Column: "name", Direction: "desc"}, {Column: "id", Direction: "asc"}}, listRequest.Order)
+	assert.Equal(t, 5, listRequest.Limit)
+}
+
+func TestFromHTTPRequest_JSONBody(t *testing.T) {
+	body := `{"page": 3, "limit": 10, "order": {"name": "asc"}, "aggregates": {"amount": "Avg"}, "filters": [{"attr": "status", "val": "A"}]}`
+	r := httptest.NewRequest(http.MethodPost, "/users/search?limit=15&filter=id:gt:5", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	listRequest, err := request.FromHTTPRequest(r)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, listRequest.Page)
+	assert.Equal(t, 15, listRequest.Limit)
+	assert.Equal(t, types.Order{{Column: "name", Direction: "asc"}}, listRequest.Order)
+	assert.Equal(t, map[string]string{"amount": "avg"}, listRequest.Aggregates)
+	assert.Equal(t, []request.FilterRequest{
+		{Attr: "status", Val: "A"},
+		{Type: "SIMPLE", Attr: "id", Opr: ">", Val: "5"},
+	}, listRequest.Filters)
+
+	listRequest.Offset = 30
+	assert.Equal(t, request.PaginationOffsetRequest{Offset: 30, Limit: 15, Order: listRequest.Order, Aggregates: listRequest.Aggregates}, listRequest.PaginationOffset())
+	assert.Equal(t, request.FindRequest{Limit: 15, Order: listRequest.Order}, listRequest.Find())
+
+	// Las solicitudes armadas a mano aceptan las funciones en mayúsculas, igual que el servicio
+	assert.NoError(t, request.Validate(request.PaginationRequest{Aggregates: map[string]string{"amount": "SUM"}}))
+	assert.NoError(t, request.Validate(request.PaginationOffsetRequest{Aggregates: map[string]string{"amount": "count"}}))
+}
+
+func TestFromHTTPRequest_FieldErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		body     string
+		expected []types.FieldError
+	}{
+		{
+			name:     "Not an integer",
+			target:   "/users?page=abc&count=maybe",
+			expected: []types.FieldError{{Field: "page", Message: "must be an integer"}, {Field: "count", Message: "must be a boolean"}},
+		},
+		{
+			name:     "Limit out of range",
+			target:   "/users?limit=100",
+			expected: []types.FieldError{{Field: "limit", Message: "must be less than or equal to 50"}},
+		},
+		{
+			name:     "Invalid operator",
+			target:   "/users?filters[0][attr]=name&filters[0][opr]=regexp",
+			expected: []types.FieldError{{Field: "filters[0].opr", Message: "must be one of: = <> > >= < <= LIKE ILIKE"}},
+		},
+		{
+			name:     "Unknown compact operator",
+			target:   "/users?filter=name:regexp:x",
+			expected: []types.FieldError{{Field: "filter", Message: "operator 'regexp' is not supported"}},
+		},
+		{
+			name:     "Unknown filter attribute",
+			target:   "/users?filters[0][attribute]=name",
+			expected: []types.FieldError{{Field: "filters[0].attribute", Message: "is not a filter attribute"}},
+		},
+		{
+			name:     "Non numeric index",
+			target:   "/users?filters[a][attr]=name",
+			expected: []types.FieldError{{Field: "filters", Message: "must use numeric indexes"}},
+		},
+		{
+			name:     "Invalid aggregate",
+			target:   "/users?aggregates[amount]=median",
+			expected: []types.FieldError{{Field: "aggregates[amount]", Message: "must be one of: sum avg min max count SUM AVG MIN MAX COUNT"}},
+		},
+		{
+			name:     "JSON type mismatch",
+			target:   "/users",
+			body:     `{"page": "2"}`,
+			expected: []types.FieldError{{Field: "page", Message: "must be a int"}},
+		},
+		{
+			name:     "Invalid JSON",
+			target:   "/users",
+			body:     `{"page": `,
+			expected: []types.FieldError{{Field: "body", Message: "is not valid json"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.body != "" {
+				r = httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader(tt.body))
+				r.Header.Set("Content-Type", "application/json")
+			}
+
+			listRequest, err := request.FromHTTPRequest(r)
+
+			assert.Nil(t, listRequest)
+			var validationError *request.ValidationError
+			if assert.True(t, errors.As(err, &validationError)) {
+				assert.Equal(t, tt.expected, validationError.Errors)
+			}
+		})
+	}
+}
+
+func TestFromHTTPRequest_Empty(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/users", nil)
+
+	listRequest, err := request.FromHTTPRequest(r)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []request.FilterRequest{}, listRequest.Filters)
+}
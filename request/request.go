@@ -3,31 +3,38 @@ package request
 import "github.com/devsstudio/gosql/types"
 
 type FilterRequest struct {
-	Type  string   `json:"limit" validate:"omitempty,oneof=SIMPLE COLUMN SUB BETWEEN NOT_BETWEEN IN NOT_IN NULL NOT_NULL DATE DATE_BETWEEN NUMERIC TERM"`
-	Attr  string   `json:"attr" validate:"omitempty"`
-	Attrs []string `json:"attrs" validate:"omitempty"`
-	Val   string   `json:"val" validate:"omitempty"`
-	Vals  []string `json:"vals" validate:"omitempty"`
-	Opr   string   `json:"opr" validate:"omitempty,oneof== <> > >= < <= LIKE ILIKE"`
-	Conn  string   `json:"conn" validate:"omitempty,oneof=AND OR"`
+	Type    string          `json:"type" validate:"omitempty,oneof=SIMPLE COLUMN SUB BETWEEN NOT_BETWEEN IN NOT_IN NULL NOT_NULL DATE DATE_BETWEEN NUMERIC TERM GROUP NOT_GROUP"`
+	Attr    string          `json:"attr" validate:"omitempty"`
+	Attrs   []string        `json:"attrs" validate:"omitempty"`
+	Val     string          `json:"val" validate:"omitempty"`
+	Vals    []string        `json:"vals" validate:"omitempty"`
+	Opr     string          `json:"opr" validate:"omitempty,oneof== <> > >= < <= LIKE ILIKE"`
+	Conn    string          `json:"conn" validate:"omitempty,oneof=AND OR"`
+	Filters []FilterRequest `json:"filters" validate:"omitempty"` // Sub-filtros de un filtro GROUP o NOT_GROUP
 }
 
 type PaginationRequest struct {
-	Count bool        `json:"count" validate:"omitempty,boolean"`
-	Page  int         `json:"page" validate:"omitempty,gte=1"`
-	Limit int         `json:"limit" validate:"omitempty,gte=1,lte=50"`
-	Order types.Order `json:"order" validate:"omitempty"`
+	Count      bool              `json:"count" validate:"omitempty,boolean"`
+	Page       int               `json:"page" validate:"omitempty,gte=1"`
+	Limit      int               `json:"limit" validate:"omitempty,gte=1,lte=50"`
+	Order      types.Order       `json:"order" validate:"omitempty"`
+	Include    []string          `json:"include" validate:"omitempty"`                                                           // Columnas ocultas a devolver
+	Aggregates map[string]string `json:"aggregates" validate:"omitempty,dive,oneof=sum avg min max count SUM AVG MIN MAX COUNT"` // Columna => función de agregación
 }
 
 type PaginationOffsetRequest struct {
-	Offset int         `json:"offset" validate:"omitempty,min=0"`
-	Limit  int         `json:"limit" validate:"omitempty,min=1,max=50"`
-	Order  types.Order `json:"order" validate:"omitempty"`
+	Offset     int               `json:"offset" validate:"omitempty,min=0"`
+	Limit      int               `json:"limit" validate:"omitempty,min=1,max=50"`
+	Order      types.Order       `json:"order" validate:"omitempty"`
+	Include    []string          `json:"include" validate:"omitempty"`                                                           // Columnas ocultas a devolver
+	Aggregates map[string]string `json:"aggregates" validate:"omitempty,dive,oneof=sum avg min max count SUM AVG MIN MAX COUNT"` // Columna => función de agregación
 }
 
 type FindRequest struct {
-	Limit int         `json:"limit" validate:"gte=1,lte=50,omitempty"`
-	Order types.Order `json:"order,omitempty"`
+	Offset  int         `json:"offset" validate:"omitempty,min=0"` // Filas a saltear, se usa junto con Limit
+	Limit   int         `json:"limit" validate:"gte=1,lte=50,omitempty"`
+	Order   types.Order `json:"order,omitempty"`
+	Include []string    `json:"include,omitempty"` // Columnas ocultas a devolver
 }
 
 type InfiniteScrollRequest struct {
@@ -35,3 +42,16 @@ type InfiniteScrollRequest struct {
 	Limit int         `json:"limit" validate:"gte=1,lte=50,omitempty"`
 	Order types.Order `json:"order,omitempty"`
 }
+
+type CursorRequest struct {
+	Cursor  string      `json:"cursor" validate:"omitempty"`
+	Limit   int         `json:"limit" validate:"omitempty,gte=1,lte=50"`
+	Order   types.Order `json:"order" validate:"omitempty"`
+	Include []string    `json:"include" validate:"omitempty"` // Columnas ocultas a devolver
+}
+
+type FacetRequest struct {
+	Columns     []string `json:"columns" validate:"required,min=1"`
+	Limit       int      `json:"limit" validate:"omitempty,gte=1,lte=100"` // Cantidad máxima de valores por faceta
+	Disjunctive bool     `json:"disjunctive" validate:"omitempty,boolean"` // Ignora los filtros de la propia columna al contarla
+}
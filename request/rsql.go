@@ -0,0 +1,356 @@
+package request
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/devsstudio/gosql/types"
+)
+
+// RSQLError indica dónde falla una expresión RSQL, Position se cuenta en caracteres desde 1
+type RSQLError struct {
+	Position int
+	Message  string
+}
+
+func (e *RSQLError) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Message, e.Position)
+}
+
+// rsqlOperator es la plantilla del filtro de cada operador de comparación
+type rsqlOperator struct {
+	filter FilterRequest
+	args   int // Cantidad de valores: 1, 2 (BETWEEN) o -1 para una lista
+}
+
+var rsqlOperators = map[string]rsqlOperator{
+	"==":         {filter: FilterRequest{Type: "SIMPLE", Opr: "="}, args: 1},
+	"!=":         {filter: FilterRequest{Type: "SIMPLE", Opr: "<>"}, args: 1},
+	"=gt=":       {filter: FilterRequest{Type: "SIMPLE", Opr: ">"}, args: 1},
+	">":          {filter: FilterRequest{Type: "SIMPLE", Opr: ">"}, args: 1},
+	"=ge=":       {filter: FilterRequest{Type: "SIMPLE", Opr: ">="}, args: 1},
+	">=":         {filter: FilterRequest{Type: "SIMPLE", Opr: ">="}, args: 1},
+	"=lt=":       {filter: FilterRequest{Type: "SIMPLE", Opr: "<"}, args: 1},
+	"<":          {filter: FilterRequest{Type: "SIMPLE", Opr: "<"}, args: 1},
+	"=le=":       {filter: FilterRequest{Type: "SIMPLE", Opr: "<="}, args: 1},
+	"<=":         {filter: FilterRequest{Type: "SIMPLE", Opr: "<="}, args: 1},
+	"=like=":     {filter: FilterRequest{Type: "SIMPLE", Opr: "LIKE"}, args: 1},
+	"=ilike=":    {filter: FilterRequest{Type: "SIMPLE", Opr: "ILIKE"}, args: 1},
+	"=in=":       {filter: FilterRequest{Type: "IN"}, args: -1},
+	"=out=":      {filter: FilterRequest{Type: "NOT_IN"}, args: -1},
+	"=between=":  {filter: FilterRequest{Type: "BETWEEN"}, args: 2},
+	"=nbetween=": {filter: FilterRequest{Type: "NOT_BETWEEN"}, args: 2},
+	"=null=":     {filter: FilterRequest{Type: "NULL"}, args: 1},
+}
+
+type rsqlParser struct {
+	input   []rune
+	pos     int
+	columns types.Columns
+}
+
+// ParseRSQL convierte una expresión RSQL/FIQL en los filtros que entiende el servicio:
+//
+//	status==A,status==B;created=ge=2024-01-01
+//	name==Jo*;(score=gt=10 or score=null=true);id=in=(1,2,3);created=between=(2024-01-01,2024-01-31)
+//
+// ";" o "and" es AND, "," u "or" es OR y AND tiene precedencia; las disyunciones se
+// devuelven como filtros GROUP. Los valores sin comillas con "*" en ==, =like= e =ilike=
+// usan LIKE con "%"; entre comillas el "*" y los comodines de LIKE se comparan literalmente.
+// Los atributos se validan contra columns, si es nil no se validan.
+func ParseRSQL(expression string, columns types.Columns) ([]FilterRequest, error) {
+	parser := &rsqlParser{input: []rune(expression), columns: columns}
+
+	parser.skipSpaces()
+	if parser.done() {
+		return []FilterRequest{}, nil
+	}
+
+	node, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	parser.skipSpaces()
+	if !parser.done() {
+		return nil, parser.errorf("unexpected character '%c'", parser.peek())
+	}
+
+	return node.Filters(), nil
+}
+
+func (parser *rsqlParser) parseOr() (FilterNode, error) {
+	return parser.parseList("OR", ',', "or", parser.parseAnd)
+}
+
+func (parser *rsqlParser) parseAnd() (FilterNode, error) {
+	return parser.parseList("AND", ';', "and", parser.parseConstraint)
+}
+
+// parseList lee uno o más nodos separados por el símbolo o la palabra del conector
+func (parser *rsqlParser) parseList(conn string, symbol rune, keyword string, parse func() (FilterNode, error)) (FilterNode, error) {
+	node, err := parse()
+	if err != nil {
+		return FilterNode{}, err
+	}
+
+	nodes := []FilterNode{node}
+	for {
+		parser.skipSpaces()
+		if parser.peek() == symbol {
+			parser.pos++
+		} else if !parser.consumeKeyword(keyword) {
+			break
+		}
+
+		node, err := parse()
+		if err != nil {
+			return FilterNode{}, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return FilterNode{Conn: conn, Children: nodes}, nil
+}
+
+func (parser *rsqlParser) parseConstraint() (FilterNode, error) {
+	parser.skipSpaces()
+	if parser.peek() != '(' {
+		return parser.parseComparison()
+	}
+
+	parser.pos++
+	node, err := parser.parseOr()
+	if err != nil {
+		return FilterNode{}, err
+	}
+	parser.skipSpaces()
+	if parser.peek() != ')' {
+		return FilterNode{}, parser.errorf("expected ')'")
+	}
+	parser.pos++
+	return node, nil
+}
+
+func (parser *rsqlParser) parseComparison() (FilterNode, error) {
+	start := parser.pos
+	attr := parser.readUnreserved()
+	if attr == "" {
+		return FilterNode{}, parser.errorf("expected attribute")
+	}
+	if parser.columns != nil {
+		if _, exists := parser.columns[attr]; !exists {
+			return FilterNode{}, &RSQLError{Position: start + 1, Message: "attribute '" + attr + "' is not allowed"}
+		}
+	}
+
+	parser.skipSpaces()
+	oprStart := parser.pos
+	opr := parser.readOperator()
+	if opr == "" {
+		return FilterNode{}, parser.errorf("expected comparison operator")
+	}
+	operator, exists := rsqlOperators[opr]
+	if !exists {
+		return FilterNode{}, &RSQLError{Position: oprStart + 1, Message: "operator '" + opr + "' is not supported"}
+	}
+
+	parser.skipSpaces()
+	argsStart := parser.pos
+	args, quoted, err := parser.parseArguments()
+	if err != nil {
+		return FilterNode{}, err
+	}
+	if operator.args == -1 && len(args) == 0 || operator.args > 0 && len(args) != operator.args {
+		return FilterNode{}, &RSQLError{Position: argsStart + 1, Message: fmt.Sprintf("operator '%s' expects %s", opr, describeArgs(operator.args))}
+	}
+
+	filter := operator.filter
+	filter.Attr = attr
+	switch {
+	case operator.args != 1:
+		filter.Vals = args
+	case opr == "=null=":
+		switch strings.ToLower(args[0]) {
+		case "true":
+		case "false":
+			filter.Type = "NOT_NULL"
+		default:
+			return FilterNode{}, &RSQLError{Position: argsStart + 1, Message: "operator '=null=' expects true or false"}
+		}
+	case (filter.Opr == "LIKE" || filter.Opr == "ILIKE") && quoted[0]:
+		filter.Val = EscapeLike(args[0])
+	case filter.Opr == "LIKE" || filter.Opr == "ILIKE":
+		filter.Val = getRSQLPattern(args[0])
+	case !quoted[0] && strings.Contains(args[0], "*"):
+		if filter.Opr != "=" {
+			return FilterNode{}, &RSQLError{Position: argsStart + 1, Message: "wildcards are only allowed with '=='"}
+		}
+		filter.Opr = "LIKE"
+		filter.Val = getRSQLPattern(args[0])
+	default:
+		filter.Val = args[0]
+	}
+
+	return FilterNode{Filter: &filter}, nil
+}
+
+// getRSQLPattern escapa los comodines de LIKE del valor y convierte los "*" en "%"
+func getRSQLPattern(value string) string {
+	return strings.ReplaceAll(EscapeLike(value), "*", "%")
+}
+
+func describeArgs(args int) string {
+	switch args {
+	case 1:
+		return "a single value"
+	case 2:
+		return "two values"
+	default:
+		return "at least one value"
+	}
+}
+
+// parseArguments lee un valor o una lista "(a,b,c)"; quoted indica qué valores venían entre comillas
+func (parser *rsqlParser) parseArguments() (values []string, quoted []bool, err error) {
+	if parser.peek() != '(' {
+		value, isQuoted, err := parser.parseValue()
+		if err != nil {
+			return nil, nil, err
+		}
+		return []string{value}, []bool{isQuoted}, nil
+	}
+
+	parser.pos++
+	for {
+		parser.skipSpaces()
+		value, isQuoted, err := parser.parseValue()
+		if err != nil {
+			return nil, nil, err
+		}
+		values = append(values, value)
+		quoted = append(quoted, isQuoted)
+
+		parser.skipSpaces()
+		switch parser.peek() {
+		case ',':
+			parser.pos++
+		case ')':
+			parser.pos++
+			return values, quoted, nil
+		default:
+			return nil, nil, parser.errorf("expected ',' or ')'")
+		}
+	}
+}
+
+// parseValue lee un valor sin comillas o entre comillas simples o dobles, con escapes "\"
+func (parser *rsqlParser) parseValue() (value string, quoted bool, err error) {
+	quote := parser.peek()
+	if quote != '"' && quote != '\'' {
+		value := parser.readUnreserved()
+		if value == "" {
+			return "", false, parser.errorf("expected value")
+		}
+		return value, false, nil
+	}
+
+	start := parser.pos
+	parser.pos++
+	var builder strings.Builder
+	for !parser.done() {
+		char := parser.input[parser.pos]
+		parser.pos++
+		switch {
+		case char == quote:
+			return builder.String(), true, nil
+		case char == '\\' && !parser.done():
+			builder.WriteRune(parser.input[parser.pos])
+			parser.pos++
+		default:
+			builder.WriteRune(char)
+		}
+	}
+	return "", true, &RSQLError{Position: start + 1, Message: "unterminated string"}
+}
+
+// readOperator lee "==", "!=", "<", "<=", ">", ">=" o la forma FIQL "=nombre="
+func (parser *rsqlParser) readOperator() string {
+	start := parser.pos
+	switch parser.peek() {
+	case '!':
+		parser.pos++
+		if parser.peek() != '=' {
+			parser.pos = start
+			return ""
+		}
+		parser.pos++
+	case '<', '>':
+		parser.pos++
+		if parser.peek() == '=' {
+			parser.pos++
+		}
+	case '=':
+		parser.pos++
+		for unicode.IsLetter(parser.peek()) {
+			parser.pos++
+		}
+		if parser.peek() != '=' {
+			parser.pos = start
+			return ""
+		}
+		parser.pos++
+	default:
+		return ""
+	}
+	return strings.ToLower(string(parser.input[start:parser.pos]))
+}
+
+func (parser *rsqlParser) readUnreserved() string {
+	start := parser.pos
+	for !parser.done() && !isRSQLReserved(parser.input[parser.pos]) {
+		parser.pos++
+	}
+	return string(parser.input[start:parser.pos])
+}
+
+func isRSQLReserved(char rune) bool {
+	return unicode.IsSpace(char) || strings.ContainsRune(`"'();,=!~<>`, char)
+}
+
+// consumeKeyword avanza sobre "and"/"or" si aparece como palabra separada
+func (parser *rsqlParser) consumeKeyword(keyword string) bool {
+	end := parser.pos + len(keyword)
+	if end >= len(parser.input) || !strings.EqualFold(string(parser.input[parser.pos:end]), keyword) {
+		return false
+	}
+	if next := parser.input[end]; !unicode.IsSpace(next) && next != '(' {
+		return false
+	}
+	parser.pos = end
+	return true
+}
+
+func (parser *rsqlParser) skipSpaces() {
+	for !parser.done() && unicode.IsSpace(parser.input[parser.pos]) {
+		parser.pos++
+	}
+}
+
+func (parser *rsqlParser) peek() rune {
+	if parser.done() {
+		return 0
+	}
+	return parser.input[parser.pos]
+}
+
+func (parser *rsqlParser) done() bool {
+	return parser.pos >= len(parser.input)
+}
+
+func (parser *rsqlParser) errorf(format string, args ...any) error {
+	return &RSQLError{Position: parser.pos + 1, Message: fmt.Sprintf(format, args...)}
+}
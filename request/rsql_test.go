@@ -0,0 +1,147 @@
+package request_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/types"
+	"github.com/stretchr/testify/assert"
+)
+
+var rsqlColumns = types.Columns{
+	"status":  "u.status",
+	"created": "u.created_at",
+	"name":    "u.name",
+	"score":   "u.score",
+	"id":      "u.id",
+}
+
+func TestParseRSQL(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		expected   []request.FilterRequest
+	}{
+		{
+			name:       "Empty",
+			expression: "  ",
+			expected:   []request.FilterRequest{},
+		},
+		{
+			name:       "Single comparison",
+			expression: "status==A",
+			expected:   []request.FilterRequest{{Type: "SIMPLE", Attr: "status", Opr: "=", Val: "A", Conn: "AND"}},
+		},
+		{
+			name:       "AND has precedence over OR",
+			expression: "status==A,status==B;created=ge=2024-01-01",
+			expected: []request.FilterRequest{{Type: "GROUP", Conn: "AND", Filters: []request.FilterRequest{
+				{Type: "SIMPLE", Attr: "status", Opr: "=", Val: "A", Conn: "OR"},
+				{Type: "GROUP", Conn: "OR", Filters: []request.FilterRequest{
+					{Type: "SIMPLE", Attr: "status", Opr: "=", Val: "B", Conn: "AND"},
+					{Type: "SIMPLE", Attr: "created", Opr: ">=", Val: "2024-01-01", Conn: "AND"},
+				}},
+			}}},
+		},
+		{
+			name:       "Parentheses and keywords",
+			expression: "(status==A or status==B) and score=gt=10",
+			expected: []request.FilterRequest{
+				{Type: "GROUP", Conn: "AND", Filters: []request.FilterRequest{
+					{Type: "SIMPLE", Attr: "status", Opr: "=", Val: "A", Conn: "OR"},
+					{Type: "SIMPLE", Attr: "status", Opr: "=", Val: "B", Conn: "OR"},
+				}},
+				{Type: "SIMPLE", Attr: "score", Opr: ">", Val: "10", Conn: "AND"},
+			},
+		},
+		{
+			name:       "List, range, null and like operators",
+			expression: "id=in=(1, 2,3);score=out=(4);created=between=(2024-01-01,2024-01-31);name=null=false;status=null=true;name==Jo*;name=ilike=*ann*;score<=5;score!=7",
+			expected: []request.FilterRequest{
+				{Type: "IN", Attr: "id", Vals: []string{"1", "2", "3"}, Conn: "AND"},
+				{Type: "NOT_IN", Attr: "score", Vals: []string{"4"}, Conn: "AND"},
+				{Type: "BETWEEN", Attr: "created", Vals: []string{"2024-01-01", "2024-01-31"}, Conn: "AND"},
+				{Type: "NOT_NULL", Attr: "name", Conn: "AND"},
+				{Type: "NULL", Attr: "status", Conn: "AND"},
+				{Type: "SIMPLE", Attr: "name", Opr: "LIKE", Val: "Jo%", Conn: "AND"},
+				{Type: "SIMPLE", Attr: "name", Opr: "ILIKE", Val: "%ann%", Conn: "AND"},
+				{Type: "SIMPLE", Attr: "score", Opr: "<=", Val: "5", Conn: "AND"},
+				{Type: "SIMPLE", Attr: "score", Opr: "<>", Val: "7", Conn: "AND"},
+			},
+		},
+		{
+			name:       "Wildcards only in unquoted values, LIKE characters are escaped",
+			expression: `name==10%_*;name=="Jo*";name=like='5*';name=ilike=a\b*`,
+			expected: []request.FilterRequest{
+				{Type: "SIMPLE", Attr: "name", Opr: "LIKE", Val: `10\%\_%`, Conn: "AND"},
+				{Type: "SIMPLE", Attr: "name", Opr: "=", Val: "Jo*", Conn: "AND"},
+				{Type: "SIMPLE", Attr: "name", Opr: "LIKE", Val: "5*", Conn: "AND"},
+				{Type: "SIMPLE", Attr: "name", Opr: "ILIKE", Val: `a\\b%`, Conn: "AND"},
+			},
+		},
+		{
+			name:       "Quoted values",
+			expression: `name=="John, \"Jr\"";status=in=('a;b','c')`,
+			expected: []request.FilterRequest{
+				{Type: "SIMPLE", Attr: "name", Opr: "=", Val: `John, "Jr"`, Conn: "AND"},
+				{Type: "IN", Attr: "status", Vals: []string{"a;b", "c"}, Conn: "AND"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters, err := request.ParseRSQL(tt.expression, rsqlColumns)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, filters)
+		})
+	}
+}
+
+func TestParseRSQL_Errors(t *testing.T) {
+	tests := []struct {
+		expression string
+		position   int
+		message    string
+	}{
+		{"status==A;", 11, "expected attribute"},
+		{"status==A;unknown==1", 11, "attribute 'unknown' is not allowed"},
+		{"status A", 8, "expected comparison operator"},
+		{"status=foo=A", 7, "operator '=foo=' is not supported"},
+		{"status==", 9, "expected value"},
+		{"status==A)", 10, "unexpected character ')'"},
+		{"(status==A", 11, "expected ')'"},
+		{"id=in=(1,2", 11, "expected ',' or ')'"},
+		{"id=between=(1)", 12, "operator '=between=' expects two values"},
+		{"id==(1,2)", 5, "operator '==' expects a single value"},
+		{"name=null=maybe", 11, "operator '=null=' expects true or false"},
+		{"name=gt=Jo*", 9, "wildcards are only allowed with '=='"},
+		{`name=="John`, 7, "unterminated string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			filters, err := request.ParseRSQL(tt.expression, rsqlColumns)
+
+			assert.Nil(t, filters)
+			var rsqlError *request.RSQLError
+			if assert.True(t, errors.As(err, &rsqlError)) {
+				assert.Equal(t, tt.position, rsqlError.Position)
+				assert.Equal(t, tt.message, rsqlError.Message)
+			}
+		})
+	}
+}
+
+func TestParseRSQL_WithoutColumns(t *testing.T) {
+	filters, err := request.ParseRSQL("any==1", nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []request.FilterRequest{{Type: "SIMPLE", Attr: "any", Opr: "=", Val: "1", Conn: "AND"}}, filters)
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `100\% \_a\\b*`, request.EscapeLike(`100% _a\b*`))
+}
@@ -1,11 +1,24 @@
 package response
 
+import "github.com/devsstudio/gosql/types"
+
 type PaginationResponse struct {
 	Page       int                      `json:"page"`
 	Limit      int                      `json:"limit"`
 	TotalPages int                      `json:"totalPages"`
 	TotalItems interface{}              `json:"totalItems"` // Ajusta el tipo según tus necesidades
 	Items      []map[string]interface{} `json:"items"`
+	Aggregates map[string]interface{}   `json:"aggregates,omitempty"`
+}
+
+// TypedPaginationResponse es PaginationResponse con los ítems ya convertidos a T
+type TypedPaginationResponse[T any] struct {
+	Page       int                    `json:"page"`
+	Limit      int                    `json:"limit"`
+	TotalPages int                    `json:"totalPages"`
+	TotalItems interface{}            `json:"totalItems"`
+	Items      []T                    `json:"items"`
+	Aggregates map[string]interface{} `json:"aggregates,omitempty"`
 }
 
 type Select2Response struct {
@@ -18,4 +31,58 @@ type PaginationOffsetResponse struct {
 	TotalItems    int                      `json:"totalItems"`
 	FilteredItems int                      `json:"filteredItems"`
 	Items         []map[string]interface{} `json:"items"`
+	Aggregates    map[string]interface{}   `json:"aggregates,omitempty"`
+}
+
+type CursorResponse struct {
+	Limit      int                      `json:"limit"`
+	NextCursor string                   `json:"nextCursor,omitempty"`
+	PrevCursor string                   `json:"prevCursor,omitempty"`
+	Items      []map[string]interface{} `json:"items"`
+}
+
+type FacetValue struct {
+	Value interface{} `json:"value"`
+	Count int         `json:"count"`
+}
+
+type FacetsResponse struct {
+	Facets map[string][]FacetValue `json:"facets"` // Valores de cada columna ordenados por cantidad
+}
+
+type CountResponse struct {
+	Count int `json:"count"`
+}
+
+// ErrorResponse es el cuerpo de las respuestas con error del handler HTTP
+type ErrorResponse struct {
+	Status  int                `json:"status"`
+	Message string             `json:"message"`
+	Errors  []types.FieldError `json:"errors,omitempty"` // Errores por campo de una solicitud inválida
+}
+
+// ODataResponse es el sobre JSON de una colección OData v4
+type ODataResponse struct {
+	Context  string                   `json:"@odata.context,omitempty"`
+	Count    *int                     `json:"@odata.count,omitempty"` // Solo con $count=true
+	Value    []map[string]interface{} `json:"value"`
+	NextLink string                   `json:"@odata.nextLink,omitempty"`
+}
+
+type ODataError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type ODataErrorResponse struct {
+	Error ODataError `json:"error"`
+}
+
+// DataTablesResponse es la respuesta del procesamiento del lado del servidor de DataTables
+type DataTablesResponse struct {
+	Draw            int                      `json:"draw"`
+	RecordsTotal    int                      `json:"recordsTotal"`
+	RecordsFiltered int                      `json:"recordsFiltered"`
+	Data            []map[string]interface{} `json:"data"`
+	Error           string                   `json:"error,omitempty"`
 }
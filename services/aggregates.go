@@ -0,0 +1,107 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/devsstudio/gosql/types"
+)
+
+// aggregateFunctions son las funciones de agregación permitidas en las solicitudes
+var aggregateFunctions = map[string]string{
+	"sum":   "SUM",
+	"avg":   "AVG",
+	"min":   "MIN",
+	"max":   "MAX",
+	"count": "COUNT",
+}
+
+// aggregateKey es una agregación validada: alias de la columna, su expresión y la función SQL
+type aggregateKey struct {
+	alias    string
+	column   string
+	function string
+}
+
+// getAggregateKeys valida las agregaciones solicitadas y las ordena por alias para que el SQL sea estable
+func (service *Pagination) getAggregateKeys(aggregates map[string]string) ([]aggregateKey, error) {
+	aliases := make([]string, 0, len(aggregates))
+	for alias := range aggregates {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	keys := make([]aggregateKey, 0, len(aliases))
+	for _, alias := range aliases {
+		def := service.getColumnDef(alias)
+		if def == nil || !def.Selectable {
+			return nil, errors.New("attribute aggregate '" + alias + "' is not allowed")
+		}
+
+		name := strings.ToLower(strings.TrimSpace(aggregates[alias]))
+		function, exists := aggregateFunctions[name]
+		if !exists {
+			return nil, errors.New("aggregate function '" + aggregates[alias] + "' not allowed for attribute '" + alias + "'")
+		}
+
+		// SUM y AVG solo tienen sentido en columnas numéricas (o sin tipo declarado)
+		if (function == "SUM" || function == "AVG") && !isNumericColumnType(def.Type) {
+			return nil, errors.New("aggregate function '" + aggregates[alias] + "' not allowed for attribute '" + alias + "'")
+		}
+
+		keys = append(keys, aggregateKey{alias: alias, column: def.Expression, function: function})
+	}
+
+	return keys, nil
+}
+
+// getAggregates calcula las agregaciones sobre el mismo WHERE del listado.
+// Con GROUP BY se agregan las filas agrupadas, por eso se usa una subconsulta.
+func (query *query) getAggregates(keys []aggregateKey) (map[string]any, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	var cols, outer, inner []string
+	for i, key := range keys {
+		alias := "gosql_aggregate_" + strconv.Itoa(i)
+		cols = append(cols, alias)
+		if query.service.group == "" {
+			outer = append(outer, key.function+"("+key.column+") as "+alias)
+		} else {
+			inner = append(inner, key.column+" as "+alias)
+			outer = append(outer, key.function+"("+alias+") as "+alias)
+		}
+	}
+
+	from := query.service.table + " WHERE " + query.where
+	if query.service.group != "" {
+		from = "(SELECT " + strings.Join(inner, ", ") + " FROM " + from + " " + query.service.group + ") gosql_aggregates"
+	}
+	sql := "SELECT " + query.getTimeoutHint() + strings.Join(outer, ", ") + " FROM " + from
+
+	items, err := query.getItems(sql, cols)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregates := make(map[string]any, len(keys))
+	for i, key := range keys {
+		var value any
+		if len(items) > 0 {
+			value = items[0][cols[i]]
+		}
+		aggregates[key.alias] = value
+	}
+
+	return aggregates, nil
+}
+
+func isNumericColumnType(columnType types.ColumnType) bool {
+	return columnType == "" ||
+		columnType == types.ColumnTypeInt ||
+		columnType == types.ColumnTypeFloat ||
+		columnType == types.ColumnTypeDecimal
+}
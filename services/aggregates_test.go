@@ -0,0 +1,91 @@
+package services_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/services"
+	"github.com/devsstudio/gosql/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaginationService_Aggregates(t *testing.T) {
+	db, mock, err := setupMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	baseParams := types.ListParams{
+		Table: "payments",
+		ColumnDefs: []types.ColumnDef{
+			types.NewColumnDef("id", "id", types.ColumnTypeInt),
+			types.NewColumnDef("status", "status", types.ColumnTypeText),
+			types.NewColumnDef("amount", "amount", types.ColumnTypeDecimal),
+			types.NewColumnDef("duration", "duration", types.ColumnTypeInt),
+		},
+	}
+	paginationService := services.PaginationService(db, baseParams)
+
+	filters := []request.FilterRequest{{Attr: "status", Val: "PAID"}}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id as id, status as status, amount as amount, duration as duration FROM payments WHERE (1 = 1) AND ((status = ?))   LIMIT 10")).
+		WithArgs("PAID").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "amount", "duration"}).AddRow(1, "PAID", "10.50", 3))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT SUM(amount) as gosql_aggregate_0, AVG(duration) as gosql_aggregate_1 FROM payments WHERE (1 = 1) AND ((status = ?))")).
+		WithArgs("PAID").
+		WillReturnRows(sqlmock.NewRows([]string{"gosql_aggregate_0", "gosql_aggregate_1"}).AddRow([]byte("10.50"), 3.0))
+
+	paginated, err := paginationService.FindPaginated(filters, request.PaginationRequest{
+		Limit:      10,
+		Aggregates: map[string]string{"amount": "sum", "duration": "avg"},
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"amount": "10.50", "duration": 3.0}, paginated.Aggregates)
+
+	// Validaciones
+	_, err = paginationService.FindPaginated(filters, request.PaginationRequest{Aggregates: map[string]string{"unknown": "sum"}}, nil)
+	assert.EqualError(t, err, "attribute aggregate 'unknown' is not allowed")
+
+	_, err = paginationService.FindPaginatedOffset(filters, request.PaginationOffsetRequest{Aggregates: map[string]string{"status": "sum"}}, nil)
+	assert.EqualError(t, err, "aggregate function 'sum' not allowed for attribute 'status'")
+
+	_, err = paginationService.FindPaginated(filters, request.PaginationRequest{Aggregates: map[string]string{"amount": "median"}}, nil)
+	assert.EqualError(t, err, "aggregate function 'median' not allowed for attribute 'amount'")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPaginationService_AggregatesGroup(t *testing.T) {
+	db, mock, err := setupMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	group := "customer_id"
+	baseParams := types.ListParams{
+		Table: "payments",
+		Group: &group,
+		ColumnDefs: []types.ColumnDef{
+			types.NewColumnDef("customer", "customer_id", types.ColumnTypeInt),
+			types.NewColumnDef("total", "SUM(amount)", types.ColumnTypeDecimal),
+		},
+	}
+	paginationService := services.PaginationService(db, baseParams)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT customer_id as customer, SUM(amount) as total FROM payments WHERE 1 = 1 GROUP BY customer_id  LIMIT 10")).
+		WillReturnRows(sqlmock.NewRows([]string{"customer", "total"}).AddRow(1, "20"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(DISTINCT customer_id) FROM payments WHERE 1 = 1")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(gosql_aggregate_0) as gosql_aggregate_0, MAX(gosql_aggregate_1) as gosql_aggregate_1 FROM (SELECT customer_id as gosql_aggregate_0, SUM(amount) as gosql_aggregate_1 FROM payments WHERE 1 = 1 GROUP BY customer_id) gosql_aggregates")).
+		WillReturnRows(sqlmock.NewRows([]string{"gosql_aggregate_0", "gosql_aggregate_1"}).AddRow(1, "20"))
+
+	paginated, err := paginationService.FindPaginatedOffset([]request.FilterRequest{}, request.PaginationOffsetRequest{
+		Limit:      10,
+		Aggregates: map[string]string{"customer": "count", "total": "max"},
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"customer": int64(1), "total": "20"}, paginated.Aggregates)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/devsstudio/gosql/helpers"
+	"github.com/devsstudio/gosql/types"
+)
+
+// getColumnDefs une las definiciones completas con las columnas del mapa Columns.
+// Las columnas del mapa se agregan ordenadas por alias con todos los permisos.
+// ColumnTypes solo completa el tipo de las columnas que no lo tienen: el Type de un
+// ColumnDef siempre tiene prioridad.
+func getColumnDefs(baseParams types.ListParams) []types.ColumnDef {
+	defs := make([]types.ColumnDef, 0, len(baseParams.ColumnDefs)+len(baseParams.Columns))
+	seen := make(map[string]struct{})
+	for _, def := range baseParams.ColumnDefs {
+		if _, exists := seen[def.Name]; exists {
+			continue
+		}
+		seen[def.Name] = struct{}{}
+		defs = append(defs, def)
+	}
+
+	names := make([]string, 0, len(baseParams.Columns))
+	for name := range baseParams.Columns {
+		if _, exists := seen[name]; !exists {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		defs = append(defs, types.NewColumnDef(name, baseParams.Columns[name], ""))
+	}
+
+	for i := range defs {
+		if defs[i].Type == "" {
+			defs[i].Type = baseParams.ColumnTypes[defs[i].Name]
+		}
+		if defs[i].Converter == nil {
+			defs[i].Converter = baseParams.Converters[defs[i].Name]
+		}
+	}
+	return defs
+}
+
+func (service *Pagination) getColumnDef(column string) *types.ColumnDef {
+	if index, exists := service.columnIndex[column]; exists {
+		return &service.columns[index]
+	}
+	return nil
+}
+
+// ColumnDef devuelve una copia de la definición de la columna con el alias indicado
+func (service *Pagination) ColumnDef(column string) (types.ColumnDef, bool) {
+	if def := service.getColumnDef(column); def != nil {
+		return *def, true
+	}
+	return types.ColumnDef{}, false
+}
+
+// ColumnDefs devuelve una copia de las definiciones de las columnas en su orden
+func (service *Pagination) ColumnDefs() []types.ColumnDef {
+	return append([]types.ColumnDef{}, service.columns...)
+}
+
+// SupportsFilter indica si la columna puede filtrarse con el tipo de filtro y el operador indicados
+func (service *Pagination) SupportsFilter(column string, filterType string, opr string) bool {
+	return service.verifyFilterColumn(column, filterType, opr) == nil
+}
+
+func (service *Pagination) getColumn(column string) *string {
+	if def := service.getColumnDef(column); def != nil {
+		return &def.Expression
+	}
+	return nil
+}
+
+// verifyFilterColumn comprueba que la columna pueda filtrarse con el tipo y operador indicados
+func (service *Pagination) verifyFilterColumn(column string, filterType string, opr string) error {
+	def := service.getColumnDef(column)
+	if def == nil || !def.Filterable {
+		return errors.New("attribute filter '" + column + "' is not allowed")
+	}
+	if len(def.FilterTypes) > 0 && !helpers.ArrayContains(def.FilterTypes, filterType) {
+		return errors.New("filter type '" + filterType + "' is not allowed for attribute '" + column + "'")
+	}
+	if len(def.Operators) > 0 && !helpers.ArrayContains(def.Operators, opr) {
+		return errors.New("operator filter '" + opr + "' not allowed for attribute '" + column + "'")
+	}
+
+	// Compatibilidad con el tipo declarado, las columnas sin tipo aceptan cualquier filtro
+	switch {
+	case def.Type == "":
+	case filterType == "NUMERIC" && !isNumericColumnType(def.Type):
+		return errors.New("filter type '" + filterType + "' is not compatible with attribute '" + column + "'")
+	case (filterType == "DATE" || filterType == "DATE_BETWEEN") && def.Type != types.ColumnTypeDate && def.Type != types.ColumnTypeTimestamp:
+		return errors.New("filter type '" + filterType + "' is not compatible with attribute '" + column + "'")
+	case (opr == "LIKE" || opr == "ILIKE") && (isNumericColumnType(def.Type) || def.Type == types.ColumnTypeBool):
+		return errors.New("operator filter '" + opr + "' is not compatible with attribute '" + column + "'")
+	}
+	return nil
+}
+
+// getSelectCols devuelve los alias y los pares "expresión as alias" de las columnas a devolver.
+// Si las exclusiones quitan todas las columnas devuelve ErrNoSelectedColumns, nunca vuelve a
+// seleccionar las excluidas.
+func (service *Pagination) getSelectCols(exclusions *[]string, inclusions []string) ([]string, []string, error) {
+	exclusionSet := make(map[string]struct{})
+	if exclusions != nil {
+		for _, excl := range *exclusions {
+			exclusionSet[excl] = struct{}{}
+		}
+	}
+
+	// Las columnas ocultas solo se devuelven si se incluyen explícitamente
+	selectable := func(def types.ColumnDef) bool {
+		return def.Selectable && (!def.Hidden || helpers.ArrayContains(inclusions, def.Name))
+	}
+
+	var cols []string
+	var selectPairs []string
+	for _, def := range service.columns {
+		if _, excluded := exclusionSet[def.Name]; !excluded && selectable(def) {
+			cols = append(cols, def.Name)
+			selectPairs = append(selectPairs, def.Expression+" as "+def.Name)
+		}
+	}
+	if len(selectPairs) == 0 {
+		return nil, nil, ErrNoSelectedColumns
+	}
+
+	return cols, selectPairs, nil
+}
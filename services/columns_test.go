@@ -0,0 +1,120 @@
+package services_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/services"
+	"github.com/devsstudio/gosql/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newColumnDefsService(t *testing.T) (*services.Pagination, sqlmock.Sqlmock) {
+	db, mock, err := setupMockDB()
+	assert.NoError(t, err)
+
+	internal := types.NewColumnDef("internal", "u.internal_code", types.ColumnTypeText)
+	internal.Hidden = true
+
+	status := types.NewColumnDef("status", "u.status", types.ColumnTypeText)
+	status.FilterTypes = []string{"SIMPLE", "IN"}
+	status.Operators = []string{"=", "<>"}
+
+	baseParams := types.ListParams{
+		Table: "users u",
+		ColumnDefs: []types.ColumnDef{
+			types.NewColumnDef("name", "u.name", types.ColumnTypeText),
+			{Name: "password", Expression: "u.password", Filterable: true},
+			{Name: "score", Expression: "u.score", Type: types.ColumnTypeInt, Selectable: true},
+			internal,
+			status,
+		},
+		// Las columnas del mapa siguen funcionando y se agregan después de las definiciones
+		Columns: types.Columns{"id": "u.id", "name": "ignored"},
+	}
+
+	return services.PaginationService(db, baseParams), mock
+}
+
+func TestPaginationService_ColumnDefsSelect(t *testing.T) {
+	paginationService, mock := newColumnDefsService(t)
+
+	defer mock.ExpectClose()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT u.name as name, u.score as score, u.status as status, u.id as id FROM users u WHERE 1 = 1")).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "score", "status", "id"}).AddRow("John", 1, "A", 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT u.name as name, u.internal_code as internal, u.id as id FROM users u WHERE 1 = 1")).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "internal", "id"}).AddRow("John", "X", 1))
+
+	_, err := paginationService.FindAll([]request.FilterRequest{}, request.FindRequest{}, nil)
+	assert.NoError(t, err)
+
+	exclusions := []string{"score", "status"}
+	items, err := paginationService.FindAll([]request.FilterRequest{}, request.FindRequest{Include: []string{"internal"}}, &exclusions)
+	assert.NoError(t, err)
+	assert.Equal(t, "X", items[0]["internal"])
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPaginationService_ColumnDefsRestrictions(t *testing.T) {
+	paginationService, mock := newColumnDefsService(t)
+
+	defer mock.ExpectClose()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users u WHERE (1 = 1) AND ((u.status <> ?) AND (u.password = ?) AND (u.name LIKE ? OR u.id LIKE ?))")).
+		WithArgs("A", "secret", "%jo%", "%jo%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	_, err := paginationService.Count([]request.FilterRequest{
+		{Attr: "status", Opr: "<>", Val: "A"},
+		{Attr: "password", Val: "secret"},
+		{Type: "TERM", Attrs: []string{"name", "id"}, Val: "%jo%"},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = paginationService.Count([]request.FilterRequest{{Attr: "score", Val: "1"}})
+	assert.EqualError(t, err, "attribute filter 'score' is not allowed")
+
+	_, err = paginationService.Count([]request.FilterRequest{{Type: "COLUMN", Attr: "name", Val: "score"}})
+	assert.EqualError(t, err, "unknown column 'score'")
+
+	_, err = paginationService.Count([]request.FilterRequest{{Type: "NULL", Attr: "status", Val: "x"}})
+	assert.EqualError(t, err, "filter type 'NULL' is not allowed for attribute 'status'")
+
+	_, err = paginationService.Count([]request.FilterRequest{{Attr: "status", Opr: "like", Val: "A%"}})
+	assert.EqualError(t, err, "operator filter 'LIKE' not allowed for attribute 'status'")
+
+	_, err = paginationService.Count([]request.FilterRequest{{Type: "NUMERIC", Attr: "id", Opr: "LIKE", Val: "1"}})
+	assert.EqualError(t, err, "operator filter 'LIKE' not allowed")
+
+	_, err = paginationService.FindAll([]request.FilterRequest{}, request.FindRequest{Order: types.Order{{Column: "password", Direction: "asc"}}}, nil)
+	assert.ErrorIs(t, err, services.ErrColumnNotSortable)
+}
+
+func TestPaginationService_ColumnTypes(t *testing.T) {
+	db, _, err := setupMockDB()
+	assert.NoError(t, err)
+
+	baseParams := types.ListParams{
+		Table: "users",
+		ColumnDefs: []types.ColumnDef{
+			types.NewColumnDef("score", "score", types.ColumnTypeInt),
+			{Name: "active", Expression: "active", Selectable: true},
+		},
+		Columns: types.Columns{"created": "created_at"},
+		// El Type de ColumnDefs tiene prioridad, ColumnTypes completa las columnas sin tipo
+		ColumnTypes: types.ColumnTypes{"score": types.ColumnTypeText, "active": types.ColumnTypeBool, "created": types.ColumnTypeDate},
+	}
+	paginationService := services.PaginationService(db, baseParams)
+
+	expected := map[string]types.ColumnType{"score": types.ColumnTypeInt, "active": types.ColumnTypeBool, "created": types.ColumnTypeDate}
+	for name, columnType := range expected {
+		def, ok := paginationService.ColumnDef(name)
+		assert.True(t, ok)
+		assert.Equal(t, columnType, def.Type, name)
+	}
+}
@@ -0,0 +1,361 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devsstudio/gosql/helpers"
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/response"
+	"github.com/devsstudio/gosql/types"
+)
+
+type (
+	// cursorPayload es el contenido firmado de un cursor
+	cursorPayload struct {
+		Keys   []string      `json:"k"`
+		Values []cursorValue `json:"v"`
+		Prev   bool          `json:"p,omitempty"`
+	}
+
+	// cursorValue conserva el tipo de los valores que JSON no distingue (fechas)
+	cursorValue struct {
+		Type  string `json:"t,omitempty"`
+		Value any    `json:"v"`
+	}
+)
+
+// FindCursor pagina por keyset: en lugar de OFFSET filtra las filas posteriores (o anteriores)
+// a las claves de orden de la última fila entregada, codificadas en un cursor firmado.
+func (service *Pagination) FindCursor(filters []request.FilterRequest, cursorRequest request.CursorRequest, exclusions *[]string) (*response.CursorResponse, error) {
+	if service.getColumn(service.cursorKey) == nil {
+		return nil, errors.New("cursor key is not configured")
+	}
+	if len(service.cursorSecret) == 0 {
+		return nil, errors.New("cursor secret is not configured")
+	}
+
+	keys, err := service.getCursorKeys(cursorRequest.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := service.newQuery(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	// Agregamos el predicado del cursor, entre paréntesis para que los filtros OR no lo anulen
+	prev := false
+	if cursorRequest.Cursor != "" {
+		payload, err := service.decodeCursor(cursorRequest.Cursor, keys)
+		if err != nil {
+			return nil, err
+		}
+		prev = payload.Prev
+		query.where = joinConditions(query.where, service.getCursorCondition(keys, payload.Values, prev, &query.placeholders))
+	}
+
+	limit := cursorRequest.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+
+	// Se pide una fila extra para saber si hay más resultados
+	query.order = getCursorOrder(keys, prev)
+	query.limit = limit + 1
+
+	cols, selectPairs, err := service.getSelectCols(exclusions, cursorRequest.Include)
+	if err != nil {
+		return nil, err
+	}
+	var hidden []string
+	for _, key := range keys {
+		if !helpers.ArrayContains(cols, key.alias) {
+			cols = append(cols, key.alias)
+			selectPairs = append(selectPairs, key.column+" as "+key.alias)
+			hidden = append(hidden, key.alias)
+		}
+	}
+	sql := query.getSql(selectPairs)
+
+	// Ejecutar la consulta, los cursores se arman con los valores sin normalizar
+	items, columnTypes, err := query.getRawItems(sql, cols)
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(items) > limit
+	if hasMore {
+		items = items[:limit]
+	}
+
+	// Al retroceder se lee en orden inverso, devolvemos las filas en el orden pedido
+	if prev {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	cursorResponse := &response.CursorResponse{
+		Limit: limit,
+		Items: items,
+	}
+
+	if len(items) > 0 {
+		if hasMore || prev {
+			cursorResponse.NextCursor, err = service.encodeCursor(keys, items[len(items)-1], false)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if (hasMore && prev) || (!prev && cursorRequest.Cursor != "") {
+			cursorResponse.PrevCursor, err = service.encodeCursor(keys, items[0], true)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := service.normalizeItems(items, cols, columnTypes); err != nil {
+		return nil, newQueryError(err)
+	}
+
+	// Quitamos las claves que no fueron seleccionadas por el cliente
+	for _, item := range items {
+		for _, alias := range hidden {
+			delete(item, alias)
+		}
+	}
+
+	return cursorResponse, nil
+}
+
+// getCursorKeys valida el orden solicitado y agrega la columna de desempate al final
+func (service *Pagination) getCursorKeys(order types.Order) ([]orderKey, error) {
+	keys, err := service.getOrderKeys(order)
+	if err != nil {
+		return nil, err
+	}
+
+	hasCursorKey := false
+	for _, key := range keys {
+		// La comparación de filas no contempla NULL, por eso no se admite NULLS FIRST/LAST
+		if key.nulls != nullsDefault {
+			return nil, &OrderError{Column: key.alias, Direction: key.nulls, Err: ErrInvalidOrderDirection}
+		}
+		if key.alias == service.cursorKey {
+			hasCursorKey = true
+		}
+	}
+
+	if !hasCursorKey {
+		// El desempate sigue la dirección de la primera clave para poder usar comparación de filas
+		desc := len(keys) > 0 && keys[0].desc
+		keys = append(keys, orderKey{alias: service.cursorKey, column: *service.getColumn(service.cursorKey), desc: desc})
+	}
+
+	return keys, nil
+}
+
+// getCursorCondition construye el predicado keyset. Si todas las claves tienen la misma dirección,
+// el motor lo soporta y los NULL no pueden quedar después del cursor, se usa comparación de filas
+// "(a, id) > (?, ?)"; en otro caso se expande en ORs contemplando los NULL de cada clave.
+func (service *Pagination) getCursorCondition(keys []orderKey, values []cursorValue, prev bool, placeholders *[]any) string {
+	operator := func(key orderKey) string {
+		if key.desc != prev {
+			return "<"
+		}
+		return ">"
+	}
+
+	// nullsBefore indica si los NULL de la clave se recorren antes que los demás valores.
+	// Al retroceder el orden se invierte y los NULL quedan del otro lado.
+	nullsBefore := func(key orderKey) bool {
+		return service.dialect.DefaultNullsFirst(key.desc) != prev
+	}
+
+	rowValues := service.dialect.SupportsRowValues()
+	for i, key := range keys {
+		// La columna de desempate es NOT NULL, las demás pueden tener NULL después del cursor
+		nullable := key.alias != service.cursorKey
+		if key.desc != keys[0].desc || values[i].value() == nil || nullable && !nullsBefore(key) {
+			rowValues = false
+		}
+	}
+
+	if rowValues {
+		var columns, params []string
+		for i, key := range keys {
+			columns = append(columns, key.column)
+			params = append(params, service.setPlaceholder(placeholders, values[i].value()))
+		}
+		return fmt.Sprintf("(%s) %s (%s)",
+			strings.Join(columns, ", "),
+			operator(keys[0]),
+			strings.Join(params, ", "),
+		)
+	}
+
+	var ors []string
+	for i, key := range keys {
+		value := values[i].value()
+
+		// Las filas posteriores en la clave i: si el cursor es NULL solo pueden serlo los valores
+		// no NULL, y solo si los NULL se recorren primero
+		var after string
+		switch {
+		case value == nil && !nullsBefore(key):
+			continue
+		case value == nil:
+			after = key.column + " IS NOT NULL"
+		case nullsBefore(key) || key.alias == service.cursorKey:
+			after = fmt.Sprintf("%s %s %s", key.column, operator(key), "%s")
+		default:
+			after = fmt.Sprintf("(%s %s %s OR %s IS NULL)", key.column, operator(key), "%s", key.column)
+		}
+
+		var ands []string
+		for j := 0; j < i; j++ {
+			if previous := values[j].value(); previous == nil {
+				ands = append(ands, keys[j].column+" IS NULL")
+			} else {
+				ands = append(ands, fmt.Sprintf("%s = %s", keys[j].column, service.setPlaceholder(placeholders, previous)))
+			}
+		}
+		if value != nil {
+			after = fmt.Sprintf(after, service.setPlaceholder(placeholders, value))
+		}
+		ands = append(ands, after)
+		if len(ands) == 1 && strings.HasPrefix(after, "(") {
+			ors = append(ors, after)
+		} else {
+			ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+		}
+	}
+
+	return strings.Join(ors, " OR ")
+}
+
+func getCursorOrder(keys []orderKey, prev bool) string {
+	var orderSQL []string
+	for _, key := range keys {
+		// Al retroceder se invierte el orden
+		if key.desc != prev {
+			orderSQL = append(orderSQL, key.column+" DESC")
+		} else {
+			orderSQL = append(orderSQL, key.column+" ASC")
+		}
+	}
+	return "ORDER BY " + strings.Join(orderSQL, ", ")
+}
+
+func (service *Pagination) encodeCursor(keys []orderKey, item map[string]any, prev bool) (string, error) {
+	payload := cursorPayload{
+		Keys: getCursorKeyNames(keys),
+		Prev: prev,
+	}
+	for _, key := range keys {
+		payload.Values = append(payload.Values, newCursorValue(item[key.alias]))
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data) + "." + base64.RawURLEncoding.EncodeToString(service.signCursor(data)), nil
+}
+
+func (service *Pagination) decodeCursor(cursor string, keys []orderKey) (*cursorPayload, error) {
+	invalid := errors.New("invalid cursor")
+
+	parts := strings.Split(cursor, ".")
+	if len(parts) != 2 {
+		return nil, invalid
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, invalid
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, invalid
+	}
+	if !hmac.Equal(signature, service.signCursor(data)) {
+		return nil, invalid
+	}
+
+	var payload cursorPayload
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, invalid
+	}
+
+	// El cursor solo es válido para el mismo orden con el que fue generado
+	if strings.Join(payload.Keys, ",") != strings.Join(getCursorKeyNames(keys), ",") || len(payload.Values) != len(keys) {
+		return nil, errors.New("cursor does not match the requested order")
+	}
+
+	return &payload, nil
+}
+
+func (service *Pagination) signCursor(data []byte) []byte {
+	mac := hmac.New(sha256.New, service.cursorSecret)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+func getCursorKeyNames(keys []orderKey) []string {
+	var names []string
+	for _, key := range keys {
+		if key.desc {
+			names = append(names, key.alias+":desc")
+		} else {
+			names = append(names, key.alias+":asc")
+		}
+	}
+	return names
+}
+
+func newCursorValue(value any) cursorValue {
+	switch v := value.(type) {
+	case time.Time:
+		return cursorValue{Type: "time", Value: v.Format(time.RFC3339Nano)}
+	case []byte:
+		return cursorValue{Value: string(v)}
+	default:
+		return cursorValue{Value: v}
+	}
+}
+
+// value recupera el valor a enlazar en la consulta
+func (cv cursorValue) value() any {
+	switch v := cv.Value.(type) {
+	case string:
+		if cv.Type == "time" {
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+				return t
+			}
+		}
+		return v
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	default:
+		return v
+	}
+}
@@ -0,0 +1,128 @@
+package services_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/services"
+	"github.com/devsstudio/gosql/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaginationService_FindCursor(t *testing.T) {
+	db, mock, err := setupMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	baseParams := types.ListParams{
+		Table:        "users",
+		Columns:      types.Columns{"id": "id", "name": "name"},
+		CursorKey:    "id",
+		CursorSecret: []byte("secret"),
+	}
+
+	paginationService := services.PaginationService(db, baseParams)
+	order := types.Order{{Column: "name", Direction: "asc"}}
+
+	// Primera página
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id as id, name as name FROM users WHERE 1 = 1  ORDER BY name ASC, id ASC LIMIT 3")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(1, "A").
+			AddRow(2, "B").
+			AddRow(3, "C"))
+
+	first, err := paginationService.FindCursor([]request.FilterRequest{}, request.CursorRequest{Limit: 2, Order: order}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(first.Items))
+	assert.NotEmpty(t, first.NextCursor)
+	assert.Empty(t, first.PrevCursor)
+
+	// Página siguiente
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id as id, name as name FROM users WHERE (1 = 1) AND ((name, id) > (?, ?))  ORDER BY name ASC, id ASC LIMIT 3")).
+		WithArgs("B", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(3, "C"))
+
+	second, err := paginationService.FindCursor([]request.FilterRequest{}, request.CursorRequest{Cursor: first.NextCursor, Limit: 2, Order: order}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(second.Items))
+	assert.Empty(t, second.NextCursor)
+	assert.NotEmpty(t, second.PrevCursor)
+
+	// Página anterior, se lee en orden inverso y los NULL de name quedan después del cursor
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id as id, name as name FROM users WHERE (1 = 1) AND ((name < ? OR name IS NULL) OR (name = ? AND id < ?))  ORDER BY name DESC, id DESC LIMIT 3")).
+		WithArgs("C", "C", 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(2, "B").
+			AddRow(1, "A"))
+
+	previous, err := paginationService.FindCursor([]request.FilterRequest{}, request.CursorRequest{Cursor: second.PrevCursor, Limit: 2, Order: order}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(previous.Items))
+	assert.Equal(t, "A", previous.Items[0]["name"])
+	assert.NotEmpty(t, previous.NextCursor)
+	assert.Empty(t, previous.PrevCursor)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPaginationService_FindCursorMixedDirections(t *testing.T) {
+	db, mock, err := setupMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	baseParams := types.ListParams{
+		Table:        "users",
+		Columns:      types.Columns{"id": "id", "name": "name"},
+		CursorKey:    "id",
+		CursorSecret: []byte("secret"),
+	}
+
+	paginationService := services.PaginationService(db, baseParams)
+	order := types.Order{{Column: "id", Direction: "asc"}, {Column: "name", Direction: "desc"}}
+	exclusions := []string{"name"}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id as id, name as name FROM users WHERE 1 = 1  ORDER BY id ASC, name DESC LIMIT 2")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
+			AddRow(1, "B").
+			AddRow(2, "A"))
+
+	first, err := paginationService.FindCursor([]request.FilterRequest{}, request.CursorRequest{Limit: 1, Order: order}, &exclusions)
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]any{{"id": int64(1)}}, first.Items)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id as id, name as name FROM users WHERE (1 = 1) AND ((id > ?) OR (id = ? AND (name < ? OR name IS NULL)))  ORDER BY id ASC, name DESC LIMIT 2")).
+		WithArgs(1, 1, "B").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	_, err = paginationService.FindCursor([]request.FilterRequest{}, request.CursorRequest{Cursor: first.NextCursor, Limit: 1, Order: order}, &exclusions)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPaginationService_FindCursorInvalid(t *testing.T) {
+	db, _, err := setupMockDB()
+	assert.NoError(t, err)
+
+	baseParams := types.ListParams{
+		Table:        "users",
+		Columns:      types.Columns{"id": "id", "name": "name"},
+		CursorKey:    "id",
+		CursorSecret: []byte("secret"),
+	}
+	paginationService := services.PaginationService(db, baseParams)
+
+	_, err = paginationService.FindCursor([]request.FilterRequest{}, request.CursorRequest{Cursor: "eyJrIjpbXX0.AAAA"}, nil)
+	assert.EqualError(t, err, "invalid cursor")
+
+	_, err = paginationService.FindCursor([]request.FilterRequest{}, request.CursorRequest{Order: types.Order{{Column: "name", Direction: "asc; DROP TABLE users"}}}, nil)
+	assert.EqualError(t, err, "order direction 'asc; DROP TABLE users' not allowed")
+
+	baseParams.CursorSecret = nil
+	_, err = services.PaginationService(db, baseParams).FindCursor([]request.FilterRequest{}, request.CursorRequest{}, nil)
+	assert.EqualError(t, err, "cursor secret is not configured")
+}
@@ -0,0 +1,116 @@
+package services_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/devsstudio/gosql/dialects"
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/services"
+	"github.com/devsstudio/gosql/types"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// namedDialector reutiliza el driver de MySQL sobre sqlmock con otro nombre de dialecto
+type namedDialector struct {
+	gorm.Dialector
+	name string
+}
+
+func (dialector namedDialector) Name() string {
+	return dialector.name
+}
+
+func setupNamedMockDB(name string) (*gorm.DB, sqlmock.Sqlmock, error) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gormDB, err := gorm.Open(namedDialector{
+		Dialector: mysql.New(mysql.Config{Conn: db, SkipInitializeWithVersion: true}),
+		name:      name,
+	}, &gorm.Config{})
+
+	return gormDB, mock, err
+}
+
+// upperDialect compara sin distinguir mayúsculas con UPPER en lugar de ILIKE
+type upperDialect struct {
+	dialects.Postgres
+}
+
+func (upperDialect) Name() string {
+	return "upper-postgres"
+}
+
+func (upperDialect) CaseInsensitiveLike(column string, pattern string) string {
+	return "UPPER(" + column + ") LIKE UPPER(" + pattern + ")"
+}
+
+func (upperDialect) SupportsCountOver() bool {
+	return false
+}
+
+func TestPaginationService_RegisteredDialect(t *testing.T) {
+	dialects.Register(upperDialect{})
+
+	db, mock, err := setupNamedMockDB("upper-postgres")
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	baseParams := types.ListParams{
+		Table:   "users",
+		Columns: types.Columns{"id": "id", "name": "name"},
+	}
+	paginationService := services.PaginationService(db, baseParams)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users WHERE (1 = 1) AND ((UPPER(name) LIKE UPPER($1)) AND (id BETWEEN ($2)::TIMESTAMP AND ($3)::TIMESTAMP + interval '1 days'))")).
+		WithArgs("%jo%", "2024-01-01", "2024-01-01").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	_, err = paginationService.Count([]request.FilterRequest{
+		{Attr: "name", Opr: "ILIKE", Val: "%jo%"},
+		{Type: "DATE", Attr: "id", Val: "2024-01-01"},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPaginationService_CountOver(t *testing.T) {
+	db, mock, err := setupPostgresMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	baseParams := types.ListParams{
+		Table:   "users",
+		Columns: types.Columns{"id": "id"},
+	}
+	paginationService := services.PaginationService(db, baseParams)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id as id, COUNT(*) OVER() as gosql_total_items FROM users WHERE 1 = 1  LIMIT 2 OFFSET 2")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "gosql_total_items"}).AddRow(3, 5).AddRow(4, 5))
+
+	paginated, err := paginationService.FindPaginated([]request.FilterRequest{}, request.PaginationRequest{Page: 2, Limit: 2, Count: true}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, paginated.TotalItems)
+	assert.Equal(t, 3, paginated.TotalPages)
+	assert.Equal(t, []map[string]any{{"id": int64(3)}, {"id": int64(4)}}, paginated.Items)
+
+	// Página fuera de rango, el total se obtiene con una consulta aparte
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id as id, COUNT(*) OVER() as gosql_total_items FROM users WHERE 1 = 1  LIMIT 2 OFFSET 18")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "gosql_total_items"}))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users WHERE 1 = 1")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+
+	paginated, err = paginationService.FindPaginated([]request.FilterRequest{}, request.PaginationRequest{Page: 10, Limit: 2, Count: true}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, paginated.TotalItems)
+	assert.Empty(t, paginated.Items)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
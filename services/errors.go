@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/devsstudio/gosql/types"
+)
+
+var (
+	// ErrColumnNotSortable indica que se pidió ordenar por una columna no permitida
+	ErrColumnNotSortable = errors.New("column is not sortable")
+	// ErrInvalidOrderDirection indica que la dirección de ordenamiento no es válida
+	ErrInvalidOrderDirection = errors.New("order direction not allowed")
+	// ErrNoSelectedColumns indica que las exclusiones dejaron la consulta sin columnas para devolver
+	ErrNoSelectedColumns = errors.New("no columns to select")
+)
+
+// OrderError describe una clave de ordenamiento rechazada. Envuelve ErrColumnNotSortable
+// o ErrInvalidOrderDirection para poder distinguirlos con errors.Is.
+type OrderError struct {
+	Column    string
+	Direction string
+	Err       error
+}
+
+func (e *OrderError) Error() string {
+	if errors.Is(e.Err, ErrColumnNotSortable) {
+		return "attribute order '" + e.Column + "' is not allowed"
+	}
+	return "order direction '" + e.Direction + "' not allowed"
+}
+
+func (e *OrderError) Unwrap() error {
+	return e.Err
+}
+
+// ValueError indica que el valor de un filtro no corresponde al tipo de la columna
+type ValueError struct {
+	Attr  string
+	Value string
+	Type  types.ColumnType
+}
+
+func (e *ValueError) Error() string {
+	return "val '" + e.Value + "' is not a valid " + string(e.Type) + " for attribute '" + e.Attr + "'"
+}
+
+// QueryError envuelve un error ocurrido al ejecutar la consulta o leer sus resultados
+// (conexión, sintaxis, tiempo agotado, conversores), para distinguirlo de una solicitud inválida.
+type QueryError struct {
+	Err error
+}
+
+func (e *QueryError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+func newQueryError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &QueryError{Err: err}
+}
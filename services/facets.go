@@ -0,0 +1,107 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/response"
+)
+
+const (
+	facetValueAlias = "gosql_facet_value"
+	facetCountAlias = "gosql_facet_count"
+)
+
+// FindFacets cuenta los valores distintos de cada columna bajo los filtros actuales.
+// Con Disjunctive cada columna se cuenta sin sus propios filtros, para que la barra
+// lateral siga mostrando las demás opciones de la columna ya filtrada.
+func (service *Pagination) FindFacets(filters []request.FilterRequest, facetRequest request.FacetRequest) (*response.FacetsResponse, error) {
+	for _, column := range facetRequest.Columns {
+		// La faceta devuelve los valores de la columna, por eso debe poder filtrarse y mostrarse
+		if def := service.getColumnDef(column); def == nil || !def.Filterable || !def.Selectable || def.Hidden {
+			return nil, errors.New("attribute facet '" + column + "' is not allowed")
+		}
+	}
+
+	limit := facetRequest.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+
+	facetsResponse := &response.FacetsResponse{Facets: map[string][]response.FacetValue{}}
+	for _, column := range facetRequest.Columns {
+		facetFilters := filters
+		if facetRequest.Disjunctive {
+			facetFilters = excludeFilters(filters, column)
+		}
+
+		query, err := service.newQuery(facetFilters)
+		if err != nil {
+			return nil, err
+		}
+		query.limit = limit
+
+		values, err := query.getFacet(*service.getColumn(column))
+		if err != nil {
+			return nil, err
+		}
+		facetsResponse.Facets[column] = values
+	}
+
+	return facetsResponse, nil
+}
+
+// getFacet agrupa por la columna y devuelve los valores más frecuentes.
+// Con GROUP BY se cuentan las filas agrupadas, por eso se usa una subconsulta.
+func (query *query) getFacet(column string) ([]response.FacetValue, error) {
+	value := column
+	from := query.service.table + " WHERE " + query.where
+	if query.service.group != "" {
+		value = facetValueAlias
+		from = "(SELECT " + column + " as " + facetValueAlias + " FROM " + from + " " + query.service.group + ") gosql_facets"
+	}
+
+	order := "ORDER BY COUNT(*) DESC, " + value + " ASC"
+	sql := "SELECT " + query.getTimeoutHint() + value + " as " + facetValueAlias + ", COUNT(*) as " + facetCountAlias +
+		" FROM " + from +
+		" GROUP BY " + value +
+		" " + query.service.dialect.LimitOffset(order, query.limit, 0)
+
+	items, err := query.getItems(sql, []string{facetValueAlias, facetCountAlias})
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]response.FacetValue, 0, len(items))
+	for _, item := range items {
+		values = append(values, response.FacetValue{
+			Value: item[facetValueAlias],
+			Count: toInt(item[facetCountAlias]),
+		})
+	}
+	return values, nil
+}
+
+// excludeFilters quita los filtros que aplican sobre la columna, también dentro de los
+// grupos. Los grupos que quedan sin filtros se descartan y el nuevo primer filtro de cada
+// nivel pasa a usar AND, porque su conector ya no une nada.
+func excludeFilters(filters []request.FilterRequest, column string) []request.FilterRequest {
+	result := make([]request.FilterRequest, 0, len(filters))
+	for _, filter := range filters {
+		filterType := strings.ToUpper(filter.Type)
+		if filterType == "GROUP" || filterType == "NOT_GROUP" {
+			filter.Filters = excludeFilters(filter.Filters, column)
+			if len(filter.Filters) == 0 {
+				continue
+			}
+		} else if filterType != "SUB" && filter.Attr == column {
+			continue
+		}
+		result = append(result, filter)
+	}
+	if len(result) > 0 {
+		result[0].Conn = "AND"
+	}
+	return result
+}
@@ -0,0 +1,111 @@
+package services_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/response"
+	"github.com/devsstudio/gosql/services"
+	"github.com/devsstudio/gosql/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaginationService_FindFacets(t *testing.T) {
+	db, mock, err := setupMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	baseParams := types.ListParams{
+		Table:   "products",
+		Columns: types.Columns{"status": "p.status", "brand": "p.brand"},
+	}
+	paginationService := services.PaginationService(db, baseParams)
+
+	filters := []request.FilterRequest{
+		{Attr: "status", Val: "A"},
+		{Attr: "brand", Val: "ACME"},
+	}
+
+	// Disjuntivo: cada faceta ignora su propio filtro
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT p.status as gosql_facet_value, COUNT(*) as gosql_facet_count FROM products WHERE (1 = 1) AND ((p.brand = ?)) GROUP BY p.status ORDER BY COUNT(*) DESC, p.status ASC LIMIT 5")).
+		WithArgs("ACME").
+		WillReturnRows(sqlmock.NewRows([]string{"gosql_facet_value", "gosql_facet_count"}).AddRow("A", 120).AddRow([]byte("B"), 14))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT p.brand as gosql_facet_value, COUNT(*) as gosql_facet_count FROM products WHERE (1 = 1) AND ((p.status = ?)) GROUP BY p.brand ORDER BY COUNT(*) DESC, p.brand ASC LIMIT 5")).
+		WithArgs("A").
+		WillReturnRows(sqlmock.NewRows([]string{"gosql_facet_value", "gosql_facet_count"}).AddRow("ACME", 7))
+
+	facets, err := paginationService.FindFacets(filters, request.FacetRequest{Columns: []string{"status", "brand"}, Limit: 5, Disjunctive: true})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]response.FacetValue{
+		"status": {{Value: "A", Count: 120}, {Value: "B", Count: 14}},
+		"brand":  {{Value: "ACME", Count: 7}},
+	}, facets.Facets)
+
+	// Conjuntivo: se aplican todos los filtros
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT p.status as gosql_facet_value, COUNT(*) as gosql_facet_count FROM products WHERE (1 = 1) AND ((p.status = ?) AND (p.brand = ?)) GROUP BY p.status ORDER BY COUNT(*) DESC, p.status ASC LIMIT 10")).
+		WithArgs("A", "ACME").
+		WillReturnRows(sqlmock.NewRows([]string{"gosql_facet_value", "gosql_facet_count"}).AddRow("A", 7))
+
+	facets, err = paginationService.FindFacets(filters, request.FacetRequest{Columns: []string{"status"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []response.FacetValue{{Value: "A", Count: 7}}, facets.Facets["status"])
+
+	_, err = paginationService.FindFacets(filters, request.FacetRequest{Columns: []string{"price"}})
+	assert.EqualError(t, err, "attribute facet 'price' is not allowed")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPaginationService_FindFacetsGroups(t *testing.T) {
+	db, mock, err := setupMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	secret := types.NewColumnDef("secret", "p.secret", types.ColumnTypeText)
+	secret.Hidden = true
+
+	baseParams := types.ListParams{
+		Table: "products",
+		ColumnDefs: []types.ColumnDef{
+			types.NewColumnDef("status", "p.status", types.ColumnTypeText),
+			types.NewColumnDef("brand", "p.brand", types.ColumnTypeText),
+			{Name: "code", Expression: "p.code", Filterable: true},
+			secret,
+		},
+	}
+	paginationService := services.PaginationService(db, baseParams)
+
+	// Los filtros de la columna se quitan también dentro de los grupos, sin importar
+	// las mayúsculas del tipo
+	filters := []request.FilterRequest{
+		{Type: "group", Filters: []request.FilterRequest{
+			{Attr: "status", Val: "A"},
+			{Attr: "status", Val: "B", Conn: "OR"},
+		}},
+		{Type: "GROUP", Filters: []request.FilterRequest{
+			{Attr: "status", Val: "C"},
+			{Attr: "brand", Val: "ACME", Conn: "OR"},
+		}},
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT p.status as gosql_facet_value, COUNT(*) as gosql_facet_count FROM products WHERE (1 = 1) AND (((p.brand = ?))) GROUP BY p.status ORDER BY COUNT(*) DESC, p.status ASC LIMIT 10")).
+		WithArgs("ACME").
+		WillReturnRows(sqlmock.NewRows([]string{"gosql_facet_value", "gosql_facet_count"}).AddRow("A", 3))
+
+	facets, err := paginationService.FindFacets(filters, request.FacetRequest{Columns: []string{"status"}, Disjunctive: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []response.FacetValue{{Value: "A", Count: 3}}, facets.Facets["status"])
+
+	// Las columnas que no se pueden mostrar no admiten facetas
+	_, err = paginationService.FindFacets([]request.FilterRequest{}, request.FacetRequest{Columns: []string{"code"}})
+	assert.EqualError(t, err, "attribute facet 'code' is not allowed")
+
+	_, err = paginationService.FindFacets([]request.FilterRequest{}, request.FacetRequest{Columns: []string{"secret"}})
+	assert.EqualError(t, err, "attribute facet 'secret' is not allowed")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/devsstudio/gosql/dialects"
+	"github.com/devsstudio/gosql/types"
+	"gorm.io/gorm"
+)
+
+// IntrospectListParams lee las columnas de una tabla o vista ("tabla" o "esquema.tabla") desde
+// information_schema o PRAGMA table_info y devuelve los ListParams con una definición tipada
+// por columna. El alias es el nombre de la columna y la expresión el mismo nombre citado
+// según el dialecto; las columnas de tipo desconocido quedan sin tipo y aceptan cualquier filtro.
+func IntrospectListParams(db *gorm.DB, table string) (types.ListParams, error) {
+	dialect := dialects.For(db)
+	introspector, ok := dialect.(dialects.Introspector)
+	if !ok {
+		return types.ListParams{}, errors.New("dialect '" + dialect.Name() + "' does not support introspection")
+	}
+
+	sql, args := introspector.ColumnsQuery(table)
+	rows, err := db.Raw(sql, bindArgs(dialect, args)...).Rows()
+	if err != nil {
+		return types.ListParams{}, err
+	}
+	defer rows.Close()
+
+	var defs []types.ColumnDef
+	for rows.Next() {
+		var name, dataType string
+		if err := rows.Scan(&name, &dataType); err != nil {
+			return types.ListParams{}, err
+		}
+		// El nombre se cita porque puede tener mayúsculas, espacios o ser una palabra reservada
+		defs = append(defs, types.NewColumnDef(name, dialect.QuoteIdentifier(name), getDatabaseColumnType(dataType)))
+	}
+	if err := rows.Err(); err != nil {
+		return types.ListParams{}, err
+	}
+
+	if len(defs) == 0 {
+		return types.ListParams{}, errors.New("table '" + table + "' not found")
+	}
+
+	return types.ListParams{
+		Table:      strings.TrimSpace(table),
+		ColumnDefs: defs,
+	}, nil
+}
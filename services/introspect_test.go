@@ -0,0 +1,94 @@
+package services_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/services"
+	"github.com/devsstudio/gosql/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntrospectListParams_SQLite(t *testing.T) {
+	db := setupSQLiteDB(t)
+	require.NoError(t, db.Exec(`CREATE VIEW user_report AS SELECT id, name, score, created_at, CAST(score AS NUMERIC(10, 2)) AS amount FROM users`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE flags (id INTEGER, active BOOLEAN, code VARCHAR(10), data JSON, blob BLOB)`).Error)
+
+	baseParams, err := services.IntrospectListParams(db, "flags")
+	require.NoError(t, err)
+	assert.Equal(t, types.ListParams{
+		Table: "flags",
+		ColumnDefs: []types.ColumnDef{
+			types.NewColumnDef("id", `"id"`, types.ColumnTypeInt),
+			types.NewColumnDef("active", `"active"`, types.ColumnTypeBool),
+			types.NewColumnDef("code", `"code"`, types.ColumnTypeText),
+			types.NewColumnDef("data", `"data"`, types.ColumnTypeJSON),
+			types.NewColumnDef("blob", `"blob"`, ""),
+		},
+	}, baseParams)
+
+	baseParams, err = services.IntrospectListParams(db, "user_report")
+	require.NoError(t, err)
+	paginationService := services.PaginationService(db, baseParams)
+
+	items, err := paginationService.FindAll(
+		[]request.FilterRequest{{Type: "DATE", Attr: "created_at", Val: "2024-01-16"}},
+		request.FindRequest{Order: types.Order{{Column: "id", Direction: "asc"}}},
+		nil,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]any{
+		{"id": int64(2), "name": "Jane", "score": int64(20), "created_at": "2024-01-16T08:00:00Z", "amount": int64(20)},
+	}, items)
+
+	// Los filtros incompatibles con el tipo se rechazan
+	_, err = paginationService.Count([]request.FilterRequest{{Type: "NUMERIC", Attr: "name", Val: "1"}})
+	assert.EqualError(t, err, "filter type 'NUMERIC' is not compatible with attribute 'name'")
+
+	_, err = paginationService.Count([]request.FilterRequest{{Type: "DATE", Attr: "score", Val: "2024-01-01"}})
+	assert.EqualError(t, err, "filter type 'DATE' is not compatible with attribute 'score'")
+
+	_, err = paginationService.Count([]request.FilterRequest{{Type: "TERM", Attrs: []string{"name", "score"}, Val: "%1%"}})
+	assert.EqualError(t, err, "operator filter 'LIKE' is not compatible with attribute 'score'")
+
+	_, err = services.IntrospectListParams(db, "missing")
+	assert.EqualError(t, err, "table 'missing' not found")
+}
+
+func TestIntrospectListParams_Postgres(t *testing.T) {
+	db, mock, err := setupPostgresMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT column_name, data_type FROM information_schema.columns WHERE table_schema = $1 AND table_name = $2 ORDER BY ordinal_position")).
+		WithArgs("reports", "sales").
+		WillReturnRows(sqlmock.NewRows([]string{"column_name", "data_type"}).
+			AddRow("id", "bigint").
+			AddRow("customer", "character varying").
+			AddRow("total", "numeric").
+			AddRow("sold_at", "timestamp without time zone").
+			AddRow("due", "date").
+			AddRow("ref", "uuid").
+			AddRow("location", "point").
+			AddRow("SoldBy", "text"))
+
+	baseParams, err := services.IntrospectListParams(db, "reports.sales")
+	assert.NoError(t, err)
+	assert.Equal(t, "reports.sales", baseParams.Table)
+	assert.Equal(t, []types.ColumnDef{
+		types.NewColumnDef("id", `"id"`, types.ColumnTypeInt),
+		types.NewColumnDef("customer", `"customer"`, types.ColumnTypeText),
+		types.NewColumnDef("total", `"total"`, types.ColumnTypeDecimal),
+		types.NewColumnDef("sold_at", `"sold_at"`, types.ColumnTypeTimestamp),
+		types.NewColumnDef("due", `"due"`, types.ColumnTypeDate),
+		types.NewColumnDef("ref", `"ref"`, types.ColumnTypeUUID),
+		types.NewColumnDef("location", `"location"`, ""),
+		types.NewColumnDef("SoldBy", `"SoldBy"`, types.ColumnTypeText),
+	}, baseParams.ColumnDefs)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/devsstudio/gosql/dialects"
+	"github.com/devsstudio/gosql/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// PaginationServiceFromModel crea el servicio a partir de un modelo de GORM. La tabla, las columnas
+// y sus tipos salen del esquema; el alias es el nombre de la etiqueta json o, si no tiene, el de la columna.
+// La etiqueta `gosql` controla la exposición:
+//
+//	gosql:"-"                          excluye el campo
+//	gosql:"filterable,sortable"        solo habilita los permisos indicados (sin etiqueta se habilitan todos)
+//	gosql:"selectable,hidden,label=X"  columna oculta por defecto y con encabezado X en las exportaciones
+//
+// Los valores de options (Where, Group, ColumnDefs adicionales, etc.) se respetan; Table y CursorKey
+// se completan con la tabla y la clave primaria del modelo cuando están vacíos.
+func PaginationServiceFromModel(db *gorm.DB, model any, options types.ListParams) (*Pagination, error) {
+	statement := &gorm.Statement{DB: db}
+	if err := statement.Parse(model); err != nil {
+		return nil, err
+	}
+	modelSchema := statement.Schema
+
+	if strings.TrimSpace(options.Table) == "" {
+		options.Table = modelSchema.Table
+	}
+	// Las columnas se califican con el alias de la tabla citado según el dialecto,
+	// p.e. "users u" => "u"."id" en PostgreSQL
+	dialect := dialects.For(db)
+	tableParts := strings.Fields(options.Table)
+	qualifier := dialect.QuoteIdentifier(tableParts[len(tableParts)-1]) + "."
+
+	defs := append([]types.ColumnDef{}, options.ColumnDefs...)
+	for _, field := range modelSchema.Fields {
+		if field.DBName == "" {
+			continue
+		}
+
+		def, include, err := getModelColumnDef(field, qualifier+dialect.QuoteIdentifier(field.DBName))
+		if err != nil {
+			return nil, err
+		}
+		if include {
+			defs = append(defs, def)
+		}
+	}
+	options.ColumnDefs = defs
+
+	if options.CursorKey == "" && len(modelSchema.PrimaryFields) == 1 {
+		for _, def := range defs {
+			if def.Expression == qualifier+dialect.QuoteIdentifier(modelSchema.PrimaryFields[0].DBName) {
+				options.CursorKey = def.Name
+				break
+			}
+		}
+	}
+
+	return PaginationService(db, options), nil
+}
+
+func getModelColumnDef(field *schema.Field, expression string) (types.ColumnDef, bool, error) {
+	name := field.DBName
+	jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	tag, hasTag := field.Tag.Lookup("gosql")
+
+	if tag == "-" || (jsonName == "-" && !hasTag) {
+		return types.ColumnDef{}, false, nil
+	}
+	if jsonName != "" && jsonName != "-" {
+		name = jsonName
+	}
+
+	def := types.NewColumnDef(name, expression, getModelColumnType(field))
+	if !hasTag {
+		return def, true, nil
+	}
+
+	def.Filterable, def.Sortable, def.Selectable = false, false, false
+	for _, option := range strings.Split(tag, ",") {
+		option = strings.TrimSpace(option)
+		switch {
+		case option == "":
+		case option == "filterable":
+			def.Filterable = true
+		case option == "sortable":
+			def.Sortable = true
+		case option == "selectable":
+			def.Selectable = true
+		case option == "hidden":
+			def.Selectable = true
+			def.Hidden = true
+		case strings.HasPrefix(option, "label="):
+			def.Label = strings.TrimPrefix(option, "label=")
+		default:
+			return types.ColumnDef{}, false, errors.New("unknown gosql option '" + option + "' on field '" + field.Name + "'")
+		}
+	}
+
+	// Una etiqueta con solo label no restringe los permisos
+	if !def.Filterable && !def.Sortable && !def.Selectable {
+		def.Filterable, def.Sortable, def.Selectable = true, true, true
+	}
+
+	return def, true, nil
+}
+
+// getModelColumnType traduce el tipo del esquema de GORM; el tipo SQL declarado (gorm:"type:...") tiene prioridad
+func getModelColumnType(field *schema.Field) types.ColumnType {
+	dataType := strings.ToLower(string(field.DataType))
+	switch {
+	case dataType == "date":
+		return types.ColumnTypeDate
+	case strings.HasPrefix(dataType, "decimal"), strings.HasPrefix(dataType, "numeric"):
+		return types.ColumnTypeDecimal
+	case dataType == "uuid":
+		return types.ColumnTypeUUID
+	case dataType == "json", dataType == "jsonb":
+		return types.ColumnTypeJSON
+	}
+
+	switch field.GORMDataType {
+	case schema.Bool:
+		return types.ColumnTypeBool
+	case schema.Int, schema.Uint:
+		return types.ColumnTypeInt
+	case schema.Float:
+		return types.ColumnTypeFloat
+	case schema.Time:
+		return types.ColumnTypeTimestamp
+	case schema.String:
+		return types.ColumnTypeText
+	}
+	return ""
+}
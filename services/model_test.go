@@ -0,0 +1,107 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/services"
+	"github.com/devsstudio/gosql/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type modelUser struct {
+	ID        int64        `json:"id" gorm:"primaryKey"`
+	Name      string       `json:"name" gorm:"column:name"`
+	Email     string       `json:"email" gosql:"filterable"`
+	Score     int          `json:"score" gosql:"sortable,selectable,label=Puntaje"`
+	MinScore  int          `gosql:"hidden"`
+	Password  string       `json:"-"`
+	Token     string       `json:"token" gosql:"-"`
+	CreatedAt time.Time    `json:"created"`
+	Orders    []modelOrder `gorm:"foreignKey:UserID"`
+}
+
+type modelOrder struct {
+	ID     int64
+	UserID int64
+}
+
+func (modelUser) TableName() string {
+	return "users"
+}
+
+func TestPaginationServiceFromModel(t *testing.T) {
+	db := setupSQLiteDB(t)
+
+	paginationService, err := services.PaginationServiceFromModel(db, &modelUser{}, types.ListParams{})
+	require.NoError(t, err)
+
+	def, exists := paginationService.ColumnDef("id")
+	assert.True(t, exists)
+	assert.Equal(t, types.ColumnDef{Name: "id", Expression: `"users"."id"`, Type: types.ColumnTypeInt, Filterable: true, Sortable: true, Selectable: true}, def)
+
+	def, _ = paginationService.ColumnDef("email")
+	assert.Equal(t, types.ColumnDef{Name: "email", Expression: `"users"."email"`, Type: types.ColumnTypeText, Filterable: true}, def)
+
+	def, _ = paginationService.ColumnDef("score")
+	assert.Equal(t, types.ColumnDef{Name: "score", Expression: `"users"."score"`, Type: types.ColumnTypeInt, Label: "Puntaje", Sortable: true, Selectable: true}, def)
+
+	def, _ = paginationService.ColumnDef("min_score")
+	assert.Equal(t, types.ColumnDef{Name: "min_score", Expression: `"users"."min_score"`, Type: types.ColumnTypeInt, Selectable: true, Hidden: true}, def)
+
+	def, _ = paginationService.ColumnDef("created")
+	assert.Equal(t, types.ColumnTypeTimestamp, def.Type)
+
+	for _, alias := range []string{"password", "Password", "token", "orders", "Orders"} {
+		_, exists := paginationService.ColumnDef(alias)
+		assert.False(t, exists, alias)
+	}
+
+	items, err := paginationService.FindAll(
+		[]request.FilterRequest{{Attr: "email", Opr: "LIKE", Val: "%mail.com"}},
+		request.FindRequest{Order: types.Order{{Column: "score", Direction: "desc"}}, Include: []string{"min_score"}},
+		nil,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]any{
+		{"id": int64(2), "name": "Jane", "score": int64(20), "min_score": int64(25), "created": "2024-01-16T08:00:00Z"},
+		{"id": int64(1), "name": "John", "score": int64(10), "min_score": int64(5), "created": "2024-01-15T10:30:00Z"},
+		{"id": int64(4), "name": "Alice", "score": nil, "min_score": int64(0), "created": "2024-03-10T12:00:00Z"},
+	}, items)
+
+	// email no es seleccionable y score no es filtrable
+	_, err = paginationService.Count([]request.FilterRequest{{Attr: "score", Val: "10"}})
+	assert.EqualError(t, err, "attribute filter 'score' is not allowed")
+
+	// La clave primaria se usa como desempate de los cursores
+	_, err = paginationService.FindCursor([]request.FilterRequest{}, request.CursorRequest{Limit: 1}, nil)
+	assert.EqualError(t, err, "cursor secret is not configured")
+
+	// Con alias de tabla y opciones propias
+	where := "u.score > :min"
+	aliased, err := services.PaginationServiceFromModel(db, &modelUser{}, types.ListParams{
+		Table:        "users u",
+		Where:        &where,
+		Placeholders: map[string]any{"min": 15},
+		CursorSecret: []byte("secret"),
+	})
+	require.NoError(t, err)
+
+	def, _ = aliased.ColumnDef("name")
+	assert.Equal(t, `"u"."name"`, def.Expression)
+
+	cursorPage, err := aliased.FindCursor([]request.FilterRequest{}, request.CursorRequest{Limit: 1}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]any{{"id": int64(2), "name": "Jane", "score": int64(20), "created": "2024-01-16T08:00:00Z"}}, cursorPage.Items)
+}
+
+func TestPaginationServiceFromModelInvalidTag(t *testing.T) {
+	type invalid struct {
+		ID int64 `gosql:"searchable"`
+	}
+
+	_, err := services.PaginationServiceFromModel(setupSQLiteDB(t), &invalid{}, types.ListParams{})
+	assert.EqualError(t, err, "unknown gosql option 'searchable' on field 'ID'")
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devsstudio/gosql/types"
+)
+
+// getConverters arma el conversor de cada columna del resultado: el Converter de la definición
+// o, si no tiene, la normalización según el tipo declarado o el que informa el driver.
+func (service *Pagination) getConverters(cols []string, columnTypes []*sql.ColumnType) []types.ValueConverter {
+	converters := make([]types.ValueConverter, len(cols))
+	for i, col := range cols {
+		var columnType types.ColumnType
+		if def := service.getColumnDef(col); def != nil {
+			if def.Converter != nil {
+				converters[i] = def.Converter
+				continue
+			}
+			columnType = def.Type
+		}
+
+		databaseType := ""
+		if i < len(columnTypes) && columnTypes[i] != nil {
+			databaseType = strings.ToUpper(columnTypes[i].DatabaseTypeName())
+		}
+
+		converters[i] = func(value any) (any, error) {
+			return normalizeValue(value, columnType, databaseType), nil
+		}
+	}
+	return converters
+}
+
+func (service *Pagination) normalizeItems(items []map[string]any, cols []string, columnTypes []*sql.ColumnType) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	converters := service.getConverters(cols, columnTypes)
+	for _, item := range items {
+		if err := normalizeRow(item, cols, converters); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func normalizeRow(row map[string]any, cols []string, converters []types.ValueConverter) error {
+	for i, col := range cols {
+		value, err := converters[i](row[col])
+		if err != nil {
+			return err
+		}
+		row[col] = value
+	}
+	return nil
+}
+
+// normalizeValue lleva los tipos propios de cada driver a valores que se serializan igual en JSON:
+// []byte a texto, decimales a json.Number, columnas JSON a json.RawMessage y fechas a RFC 3339.
+func normalizeValue(value any, columnType types.ColumnType, databaseType string) any {
+	switch v := value.(type) {
+	case []byte:
+		return normalizeText(string(v), v, columnType, databaseType)
+	case string:
+		return normalizeText(v, nil, columnType, databaseType)
+	case time.Time:
+		if columnType == types.ColumnTypeDate || (columnType == "" && getDatabaseColumnType(databaseType) == types.ColumnTypeDate) {
+			return v.Format("2006-01-02")
+		}
+		return v.Format(time.RFC3339Nano)
+	case int64:
+		// MySQL y SQLite devuelven los booleanos como 0/1
+		if columnType == types.ColumnTypeBool {
+			return v != 0
+		}
+	}
+	return value
+}
+
+// normalizeText convierte un valor recibido como texto; raw son los bytes originales, si los hay
+func normalizeText(text string, raw []byte, columnType types.ColumnType, databaseType string) any {
+	if columnType == "" {
+		columnType = getDatabaseColumnType(databaseType)
+	}
+
+	switch columnType {
+	case types.ColumnTypeInt:
+		if number, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return number
+		}
+	case types.ColumnTypeFloat:
+		if number, err := strconv.ParseFloat(text, 64); err == nil {
+			return number
+		}
+	case types.ColumnTypeDecimal:
+		// Se mantiene la precisión, json.Number se serializa como número
+		if decimalRegexp.MatchString(text) {
+			return json.Number(text)
+		}
+	case types.ColumnTypeBool:
+		if b, err := strconv.ParseBool(text); err == nil {
+			return b
+		}
+	case types.ColumnTypeJSON:
+		if json.Valid([]byte(text)) {
+			return json.RawMessage(text)
+		}
+	case types.ColumnTypeDate, types.ColumnTypeTimestamp:
+		// SQLite y MySQL sin parseTime devuelven las fechas como texto
+		if t, err := parseTime(text, false); err == nil {
+			return normalizeValue(t, columnType, databaseType)
+		}
+	}
+
+	// Los binarios se conservan como bytes
+	if raw != nil && isBinaryDatabaseType(databaseType) {
+		return raw
+	}
+	return text
+}
+
+// getDatabaseColumnType deduce el tipo de columna a partir del tipo SQL, sea el que informa el
+// driver ("NEWDECIMAL", "UNSIGNED BIGINT") o el declarado en el esquema ("character varying(50)")
+func getDatabaseColumnType(databaseType string) types.ColumnType {
+	name := strings.ToLower(strings.TrimSpace(databaseType))
+	if index := strings.Index(name, "("); index >= 0 {
+		name = strings.TrimSpace(name[:index])
+	}
+	name = strings.TrimPrefix(name, "unsigned ")
+	name = strings.TrimSuffix(name, " unsigned")
+
+	switch {
+	case name == "":
+		return ""
+	case name == "bool" || name == "boolean":
+		return types.ColumnTypeBool
+	case name == "int" || name == "integer" || name == "tinyint" || name == "smallint" || name == "mediumint" || name == "bigint" ||
+		name == "int2" || name == "int4" || name == "int8" || name == "serial" || name == "bigserial" || name == "smallserial":
+		return types.ColumnTypeInt
+	case name == "float" || name == "float4" || name == "float8" || name == "real" || strings.HasPrefix(name, "double"):
+		return types.ColumnTypeFloat
+	case name == "decimal" || name == "newdecimal" || name == "numeric" || name == "money" || name == "smallmoney":
+		return types.ColumnTypeDecimal
+	case name == "date":
+		return types.ColumnTypeDate
+	case strings.HasPrefix(name, "datetime") || strings.HasPrefix(name, "timestamp") || name == "smalldatetime" || name == "timestamptz":
+		return types.ColumnTypeTimestamp
+	case name == "uuid" || name == "uniqueidentifier":
+		return types.ColumnTypeUUID
+	case name == "json" || name == "jsonb":
+		return types.ColumnTypeJSON
+	case strings.Contains(name, "char") || strings.HasSuffix(name, "text") || name == "clob" || name == "string" || name == "citext":
+		return types.ColumnTypeText
+	}
+	return ""
+}
+
+func isBinaryDatabaseType(databaseType string) bool {
+	switch databaseType {
+	case "BLOB", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB", "BINARY", "VARBINARY", "BYTEA", "GEOMETRY", "BIT":
+		return true
+	}
+	return false
+}
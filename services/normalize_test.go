@@ -0,0 +1,101 @@
+package services_test
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/services"
+	"github.com/devsstudio/gosql/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaginationService_NormalizeValues(t *testing.T) {
+	db, mock, err := setupMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	baseParams := types.ListParams{
+		Table: "orders",
+		ColumnDefs: []types.ColumnDef{
+			types.NewColumnDef("id", "id", types.ColumnTypeInt),
+			types.NewColumnDef("code", "code", ""),
+			types.NewColumnDef("total", "total", types.ColumnTypeDecimal),
+			types.NewColumnDef("paid", "paid", types.ColumnTypeBool),
+			types.NewColumnDef("meta", "meta", types.ColumnTypeJSON),
+			types.NewColumnDef("due", "due", types.ColumnTypeDate),
+			types.NewColumnDef("created", "created_at", types.ColumnTypeTimestamp),
+			types.NewColumnDef("price", "price", ""),
+			types.NewColumnDef("raw", "raw", ""),
+		},
+		Converters: map[string]types.ValueConverter{
+			"code": func(value any) (any, error) {
+				if value == nil {
+					return nil, nil
+				}
+				return strings.ToUpper(string(value.([]byte))), nil
+			},
+		},
+	}
+	paginationService := services.PaginationService(db, baseParams)
+
+	created := time.Date(2024, 1, 15, 10, 30, 0, 500, time.UTC)
+	columns := []*sqlmock.Column{
+		sqlmock.NewColumn("id").OfType("BIGINT", int64(0)),
+		sqlmock.NewColumn("code").OfType("VARCHAR", ""),
+		sqlmock.NewColumn("total").OfType("DECIMAL", ""),
+		sqlmock.NewColumn("paid").OfType("TINYINT", int64(0)),
+		sqlmock.NewColumn("meta").OfType("JSON", ""),
+		sqlmock.NewColumn("due").OfType("DATE", ""),
+		sqlmock.NewColumn("created").OfType("DATETIME", time.Time{}),
+		sqlmock.NewColumn("price").OfType("DECIMAL", ""),
+		sqlmock.NewColumn("raw").OfType("BLOB", []byte{}),
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id as id, code as code, total as total, paid as paid, meta as meta, due as due, created_at as created, price as price, raw as raw FROM orders WHERE 1 = 1")).
+		WillReturnRows(mock.NewRowsWithColumnDefinition(columns...).
+			AddRow([]byte("1"), []byte("ab-1"), []byte("10.50"), int64(1), []byte(`{"a":1}`), []byte("2024-01-31"), created, []byte("3.25"), []byte{0x01, 0x02}).
+			AddRow(int64(2), nil, nil, int64(0), nil, nil, nil, nil, nil))
+
+	items, err := paginationService.FindAll([]request.FilterRequest{}, request.FindRequest{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]any{
+		{
+			"id":      int64(1),
+			"code":    "AB-1",
+			"total":   json.Number("10.50"),
+			"paid":    true,
+			"meta":    json.RawMessage(`{"a":1}`),
+			"due":     "2024-01-31",
+			"created": "2024-01-15T10:30:00.0000005Z",
+			"price":   json.Number("3.25"),
+			"raw":     []byte{0x01, 0x02},
+		},
+		{"id": int64(2), "code": nil, "total": nil, "paid": false, "meta": nil, "due": nil, "created": nil, "price": nil, "raw": nil},
+	}, items)
+
+	data, err := json.Marshal(items[0])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":1,"code":"AB-1","total":10.50,"paid":true,"meta":{"a":1},"due":"2024-01-31","created":"2024-01-15T10:30:00.0000005Z","price":3.25,"raw":"AQI="}`, string(data))
+
+	// Un error del conversor corta la consulta
+	failing := types.NewColumnDef("id", "id", types.ColumnTypeInt)
+	failing.Converter = func(value any) (any, error) {
+		return nil, errors.New("broken")
+	}
+	failingService := services.PaginationService(db, types.ListParams{Table: "orders", ColumnDefs: []types.ColumnDef{failing}})
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id as id FROM orders WHERE 1 = 1")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(int64(1)))
+
+	_, err = failingService.FindAll([]request.FilterRequest{}, request.FindRequest{}, nil)
+	assert.EqualError(t, err, "broken")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
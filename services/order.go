@@ -0,0 +1,97 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/devsstudio/gosql/types"
+)
+
+const (
+	nullsDefault = ""
+	nullsFirst   = "NULLS FIRST"
+	nullsLast    = "NULLS LAST"
+)
+
+// orderKey es una clave de ordenamiento ya validada
+type orderKey struct {
+	alias  string
+	column string
+	desc   bool
+	nulls  string
+}
+
+// getOrderKeys valida el orden solicitado: solo columnas declaradas y direcciones
+// ASC/DESC con NULLS FIRST/LAST opcional. Las claves repetidas se ignoran.
+func (service *Pagination) getOrderKeys(order types.Order) ([]orderKey, error) {
+	var keys []orderKey
+	seen := make(map[string]struct{})
+	for _, orderBy := range order {
+		def := service.getColumnDef(orderBy.Column)
+		if def == nil || !def.Sortable {
+			return nil, &OrderError{Column: orderBy.Column, Direction: orderBy.Direction, Err: ErrColumnNotSortable}
+		}
+
+		desc, nulls, ok := parseOrderDirection(orderBy.Direction)
+		if !ok {
+			return nil, &OrderError{Column: orderBy.Column, Direction: orderBy.Direction, Err: ErrInvalidOrderDirection}
+		}
+
+		if _, exists := seen[orderBy.Column]; exists {
+			continue
+		}
+		seen[orderBy.Column] = struct{}{}
+
+		keys = append(keys, orderKey{alias: orderBy.Column, column: def.Expression, desc: desc, nulls: nulls})
+	}
+	return keys, nil
+}
+
+func (service *Pagination) getOrder(order types.Order) (string, error) {
+	keys, err := service.getOrderKeys(order)
+	if err != nil {
+		return "", err
+	}
+
+	var orderSQL []string
+	for _, key := range keys {
+		orderSQL = append(orderSQL, service.getOrderKeySql(key))
+	}
+	if len(orderSQL) > 0 {
+		return "ORDER BY " + strings.Join(orderSQL, ", "), nil
+	}
+	return "", nil
+}
+
+func (service *Pagination) getOrderKeySql(key orderKey) string {
+	direction := "ASC"
+	if key.desc {
+		direction = "DESC"
+	}
+
+	if key.nulls == nullsDefault {
+		return key.column + " " + direction
+	}
+
+	return service.dialect.NullsOrder(key.column, direction, key.nulls)
+}
+
+// parseOrderDirection interpreta "asc", "desc", "asc nulls first", "nulls last", etc.
+func parseOrderDirection(value string) (desc bool, nulls string, ok bool) {
+	words := strings.Fields(strings.ToUpper(value))
+
+	if len(words) > 0 && (words[0] == "ASC" || words[0] == "DESC") {
+		desc = words[0] == "DESC"
+		words = words[1:]
+	}
+
+	switch strings.Join(words, " ") {
+	case "":
+		return desc, nullsDefault, true
+	case nullsFirst:
+		return desc, nullsFirst, true
+	case nullsLast:
+		return desc, nullsLast, true
+	default:
+		return false, nullsDefault, false
+	}
+}
@@ -0,0 +1,116 @@
+package services_test
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/services"
+	"github.com/devsstudio/gosql/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrder_UnmarshalJSON(t *testing.T) {
+	var findRequest request.FindRequest
+	err := json.Unmarshal([]byte(`{"order": {"status": "desc", "name": "asc nulls last", "id": "asc"}}`), &findRequest)
+	assert.NoError(t, err)
+	assert.Equal(t, types.Order{
+		{Column: "status", Direction: "desc"},
+		{Column: "name", Direction: "asc nulls last"},
+		{Column: "id", Direction: "asc"},
+	}, findRequest.Order)
+
+	err = json.Unmarshal([]byte(`{"order": [{"column": "id", "direction": "desc"}]}`), &findRequest)
+	assert.NoError(t, err)
+	assert.Equal(t, types.Order{{Column: "id", Direction: "desc"}}, findRequest.Order)
+
+	data, err := json.Marshal(types.Order{{Column: "b", Direction: "asc"}, {Column: "a", Direction: "desc"}})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"b":"asc","a":"desc"}`, string(data))
+
+	data, err = json.Marshal(types.Order(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, `null`, string(data))
+
+	data, err = json.Marshal(types.Order{})
+	assert.NoError(t, err)
+	assert.Equal(t, `{}`, string(data))
+}
+
+func TestOrderFromMap(t *testing.T) {
+	assert.Equal(t, types.Order{
+		{Column: "id", Direction: "desc"},
+		{Column: "name", Direction: "asc"},
+	}, types.OrderFromMap(map[string]string{"name": "asc", "id": "desc"}))
+
+	assert.Nil(t, types.OrderFromMap(nil))
+}
+
+func TestPaginationService_OrderMySQL(t *testing.T) {
+	db, mock, err := setupMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	baseParams := types.ListParams{
+		Table:   "users",
+		Columns: types.Columns{"id": "u.id", "name": "u.name", "status": "u.status"},
+	}
+	paginationService := services.PaginationService(db, baseParams)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT u.id as id FROM users WHERE 1 = 1  ORDER BY u.status DESC, u.name IS NULL DESC, u.name ASC, u.id IS NULL ASC, u.id DESC LIMIT 5")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	order := types.Order{
+		{Column: "status", Direction: "DESC"},
+		{Column: "name", Direction: "nulls first"},
+		{Column: "id", Direction: " desc  NULLS  last "},
+		{Column: "status", Direction: "asc"},
+	}
+	exclusions := []string{"name", "status"}
+	_, err = paginationService.FindAll([]request.FilterRequest{}, request.FindRequest{Limit: 5, Order: order}, &exclusions)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPaginationService_OrderPostgres(t *testing.T) {
+	db, mock, err := setupPostgresMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	baseParams := types.ListParams{
+		Table:   "users",
+		Columns: types.Columns{"id": "u.id", "name": "u.name"},
+	}
+	paginationService := services.PaginationService(db, baseParams)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT u.id as id, u.name as name FROM users WHERE 1 = 1  ORDER BY u.name DESC NULLS LAST, u.id ASC LIMIT 5")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John"))
+
+	order := types.Order{{Column: "name", Direction: "desc nulls last"}, {Column: "id"}}
+	_, err = paginationService.FindAll([]request.FilterRequest{}, request.FindRequest{Limit: 5, Order: order}, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPaginationService_OrderErrors(t *testing.T) {
+	db, _, err := setupMockDB()
+	assert.NoError(t, err)
+
+	paginationService := services.PaginationService(db, types.ListParams{Table: "users", Columns: types.Columns{"id": "id"}})
+
+	_, err = paginationService.FindAll([]request.FilterRequest{}, request.FindRequest{Order: types.Order{{Column: "password", Direction: "asc"}}}, nil)
+	var orderErr *services.OrderError
+	assert.True(t, errors.As(err, &orderErr))
+	assert.Equal(t, "password", orderErr.Column)
+	assert.ErrorIs(t, err, services.ErrColumnNotSortable)
+	assert.EqualError(t, err, "attribute order 'password' is not allowed")
+
+	_, err = paginationService.FindPaginated([]request.FilterRequest{}, request.PaginationRequest{Order: types.Order{{Column: "id", Direction: "asc, (SELECT 1)"}}}, nil)
+	assert.ErrorIs(t, err, services.ErrInvalidOrderDirection)
+	assert.EqualError(t, err, "order direction 'asc, (SELECT 1)' not allowed")
+}
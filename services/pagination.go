@@ -1,52 +1,71 @@
 package services
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"math"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/devsstudio/gosql/constants"
+	"github.com/devsstudio/gosql/dialects"
 	"github.com/devsstudio/gosql/helpers"
 	"github.com/devsstudio/gosql/request"
 	"github.com/devsstudio/gosql/response"
 	"github.com/devsstudio/gosql/types"
 
-	"gorm.io/driver/mysql"
-	"gorm.io/driver/postgres"
-
 	"github.com/go-playground/validator/v10"
 	"gorm.io/gorm"
 )
 
+// countOverAlias es el alias de la columna COUNT(*) OVER() que se quita de los resultados
+const countOverAlias = "gosql_total_items"
+
 type (
+	// Pagination es la definición inmutable de un listado. Puede construirse una sola vez
+	// y compartirse entre goroutines, cada llamada Find* arma su propio query.
 	Pagination struct {
 		db                   *gorm.DB
-		columns              types.Columns
+		dialect              dialects.Dialect
+		columns              []types.ColumnDef
+		columnIndex          map[string]int
 		table                string
 		originalWhere        string
-		where                string
 		group                string
-		order                string
-		offsetLimit          string
 		originalPlaceholders []any
+		basePlaceholders     map[string]any
+		subqueries           types.Subqueries
+		cursorKey            string
+		cursorSecret         []byte
+		timeout              time.Duration
+		maxInValues          int
+	}
+
+	// query es el plan de una ejecución: condiciones, orden, paginación y valores enlazados
+	query struct {
+		service      *Pagination
+		where        string
+		order        string
+		limit        int
+		offset       int
+		placeholders []any
 	}
 )
 
 func PaginationService(db *gorm.DB, baseParams types.ListParams) *Pagination {
 
 	originalWhere := ""
-	where := ""
 	group := ""
 	placeholders := []any{}
 	// Adding WHERE
 	if baseParams.Where != nil && len(strings.TrimSpace(baseParams.Table)) > 0 {
 		originalWhere = *baseParams.Where
-		where = *baseParams.Where
 	} else {
 		originalWhere = "1 = 1"
-		where = "1 = 1"
 	}
 
 	// Adding GROUP
@@ -58,58 +77,88 @@ func PaginationService(db *gorm.DB, baseParams types.ListParams) *Pagination {
 
 	service := &Pagination{
 		db:                   db,
-		columns:              baseParams.Columns,
+		dialect:              dialects.For(db),
+		columns:              getColumnDefs(baseParams),
+		columnIndex:          map[string]int{},
 		table:                baseParams.Table,
 		originalWhere:        originalWhere,
-		where:                where,
 		group:                group,
 		originalPlaceholders: placeholders,
-		order:                "",
-		offsetLimit:          "",
+		basePlaceholders:     baseParams.Placeholders,
+		subqueries:           baseParams.Subqueries,
+		cursorKey:            baseParams.CursorKey,
+		cursorSecret:         baseParams.CursorSecret,
+		timeout:              baseParams.Timeout,
+		maxInValues:          baseParams.MaxInValues,
+	}
+	if service.maxInValues <= 0 {
+		service.maxInValues = constants.DEFAULT_MAX_IN_VALUES
+	}
+	for index, def := range service.columns {
+		service.columnIndex[def.Name] = index
 	}
 	service.table = service.replaceOriginalPlaceholders(baseParams.Table, baseParams.Placeholders, &service.originalPlaceholders)
 	service.originalWhere = service.replaceOriginalPlaceholders(service.originalWhere, baseParams.Placeholders, &service.originalPlaceholders)
 	return service
 }
 
-func (service *Pagination) FindAll(filters []request.FilterRequest, findRequest request.FindRequest, exclusions *[]string) ([]map[string]any, error) {
-	placeholders := make([]any, len(service.originalPlaceholders))
-	copy(placeholders, service.originalPlaceholders)
+// WithContext devuelve una copia del servicio cuyas consultas se cancelan junto con ctx
+func (service *Pagination) WithContext(ctx context.Context) *Pagination {
+	clone := *service
+	clone.db = service.db.WithContext(ctx)
+	return &clone
+}
+
+// WithTimeout devuelve una copia del servicio con otro tiempo máximo por consulta
+func (service *Pagination) WithTimeout(timeout time.Duration) *Pagination {
+	clone := *service
+	clone.timeout = timeout
+	return &clone
+}
 
-	var err error = nil
-	service.where, err = service.getFilters(filters, service.originalWhere, &placeholders)
+func (service *Pagination) FindAll(filters []request.FilterRequest, findRequest request.FindRequest, exclusions *[]string) ([]map[string]any, error) {
+	query, err := service.newQuery(filters)
 	if err != nil {
 		return nil, err
 	}
 
-	service.offsetLimit = getLimit(findRequest)
-	service.order = service.getOrder(findRequest.Order)
+	query.limit, query.offset = getLimit(findRequest), findRequest.Offset
+	query.order, err = service.getOrder(findRequest.Order)
+	if err != nil {
+		return nil, err
+	}
 
-	cols, selectPairs := service.getSelectCols(exclusions)
-	sql := service.getSql(selectPairs)
+	cols, selectPairs, err := service.getSelectCols(exclusions, findRequest.Include)
+	if err != nil {
+		return nil, err
+	}
+	sql := query.getSql(selectPairs)
 
 	// Ejecutar la consulta
-	return service.getItems(sql, cols, placeholders)
+	return query.getItems(sql, cols)
 }
 
 func (service *Pagination) FindSelect2(filters []request.FilterRequest, infiniteScroll request.InfiniteScrollRequest, valueAttribute, textAttribute string) (*response.Select2Response, error) {
-	placeholders := make([]any, len(service.originalPlaceholders))
-	copy(placeholders, service.originalPlaceholders)
+	selectPairs, err := service.getSelect2Pairs(valueAttribute, textAttribute)
+	if err != nil {
+		return nil, err
+	}
 
-	var err error = nil
-	service.where, err = service.getFilters(filters, service.originalWhere, &placeholders)
+	query, err := service.newQuery(filters)
 	if err != nil {
 		return nil, err
 	}
 
-	service.offsetLimit = getInfiniteScroll(infiniteScroll)
-	service.order = service.getOrder(infiniteScroll.Order)
+	query.limit, query.offset = getInfiniteScroll(infiniteScroll)
+	query.order, err = service.getOrder(infiniteScroll.Order)
+	if err != nil {
+		return nil, err
+	}
 
-	selectPairs := service.getSelect2Pairs(valueAttribute, textAttribute)
-	sql := service.getSql(selectPairs)
+	sql := query.getSql(selectPairs)
 
 	// Ejecutar la consulta
-	items, err := service.getItems(sql, []string{"value", "label"}, placeholders)
+	items, err := query.getItems(sql, []string{"value", "label"})
 	if err != nil {
 		return nil, err
 	}
@@ -118,30 +167,36 @@ func (service *Pagination) FindSelect2(filters []request.FilterRequest, infinite
 }
 
 func (service *Pagination) FindPaginated(filters []request.FilterRequest, pagination request.PaginationRequest, exclusions *[]string) (*response.PaginationResponse, error) {
-	placeholders := make([]any, len(service.originalPlaceholders))
-	copy(placeholders, service.originalPlaceholders)
+	query, err := service.newQuery(filters)
+	if err != nil {
+		return nil, err
+	}
 
-	var err error = nil
-	service.where, err = service.getFilters(filters, service.originalWhere, &placeholders)
+	query.limit, query.offset = getPagination(pagination)
+	query.order, err = service.getOrder(pagination.Order)
 	if err != nil {
 		return nil, err
 	}
 
-	service.offsetLimit = getPagination(pagination)
-	service.order = service.getOrder(pagination.Order)
+	aggregateKeys, err := service.getAggregateKeys(pagination.Aggregates)
+	if err != nil {
+		return nil, err
+	}
 
-	cols, selectPairs := service.getSelectCols(exclusions)
-	sql := service.getSql(selectPairs)
+	cols, selectPairs, err := service.getSelectCols(exclusions, pagination.Include)
+	if err != nil {
+		return nil, err
+	}
 
 	// Ejecutar la consulta
-	items, err := service.getItems(sql, cols, placeholders)
+	items, totalItems, err := query.getItemsAndCount(cols, selectPairs, pagination.Count)
 	if err != nil {
 		return nil, err
 	}
 
-	totalItems := 0
-	if pagination.Count {
-		totalItems = service.internalCount(placeholders)
+	aggregates, err := query.getAggregates(aggregateKeys)
+	if err != nil {
+		return nil, err
 	}
 
 	totalPages := 1
@@ -155,20 +210,22 @@ func (service *Pagination) FindPaginated(filters []request.FilterRequest, pagina
 		TotalPages: totalPages,
 		TotalItems: totalItems,
 		Items:      items,
+		Aggregates: aggregates,
 	}
 
 	return response, nil
 }
 
 func (service *Pagination) FindPaginatedOffset(filters []request.FilterRequest, pagination request.PaginationOffsetRequest, exclusions *[]string) (*response.PaginationOffsetResponse, error) {
-	placeholders := make([]any, len(service.originalPlaceholders))
-	copy(placeholders, service.originalPlaceholders)
+	aggregateKeys, err := service.getAggregateKeys(pagination.Aggregates)
+	if err != nil {
+		return nil, err
+	}
 
-	var err error = nil
 	// Contar sin filtros
 	totalItems := 0
 	if len(filters) > 0 {
-		count, err := service.Count(nil) // Se pasa nil porque los filtros aún no están establecidos
+		count, err := service.Count([]request.FilterRequest{})
 		if err != nil {
 			return nil, err
 		}
@@ -176,49 +233,72 @@ func (service *Pagination) FindPaginatedOffset(filters []request.FilterRequest,
 	}
 
 	// Preparar filtros y cláusulas
-	service.where, err = service.getFilters(filters, "", &placeholders)
+	query, err := service.newQuery(filters)
 	if err != nil {
 		return nil, err
 	}
 
-	service.offsetLimit = getPaginationOffset(pagination)
-	service.order = service.getOrder(pagination.Order)
-
-	cols, selectPairs := service.getSelectCols(exclusions)
-	sql := service.getSql(selectPairs)
+	query.limit, query.offset = getPaginationOffset(pagination)
+	query.order, err = service.getOrder(pagination.Order)
+	if err != nil {
+		return nil, err
+	}
 
-	// Ejecutar la consulta
-	items, err := service.getItems(sql, cols, placeholders)
+	cols, selectPairs, err := service.getSelectCols(exclusions, pagination.Include)
 	if err != nil {
 		return nil, err
 	}
 
-	// Contar los ítems filtrados
-	filteredItems := service.internalCount(placeholders)
+	// Ejecutar la consulta y contar los ítems filtrados
+	items, filteredItems, err := query.getItemsAndCount(cols, selectPairs, true)
+	if err != nil {
+		return nil, err
+	}
 	if len(filters) == 0 {
 		totalItems = filteredItems
 	}
 
+	aggregates, err := query.getAggregates(aggregateKeys)
+	if err != nil {
+		return nil, err
+	}
+
 	return &response.PaginationOffsetResponse{
 		Offset:        pagination.Offset,
 		Limit:         pagination.Limit,
 		TotalItems:    totalItems,
 		FilteredItems: filteredItems,
 		Items:         items,
+		Aggregates:    aggregates,
 	}, nil
 }
 
 func (service *Pagination) Count(filters []request.FilterRequest) (int, error) {
+	query, err := service.newQuery(filters)
+	if err != nil {
+		return 0, err
+	}
+
+	return query.internalCount()
+}
+
+// newQuery crea el plan de una ejecución con los placeholders base y las condiciones de los filtros
+func (service *Pagination) newQuery(filters []request.FilterRequest) (*query, error) {
 	placeholders := make([]any, len(service.originalPlaceholders))
 	copy(placeholders, service.originalPlaceholders)
 
-	var err error = nil
-	service.where, err = service.getFilters(filters, service.originalWhere, &placeholders)
+	// Los filtros se arman aparte y se encierran entre paréntesis para que un conector OR
+	// del cliente no escape de la condición base (p.e. la de un tenant)
+	conditions, err := service.getFilters(filters, "", &placeholders)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	return service.internalCount(placeholders), nil
+	return &query{
+		service:      service,
+		where:        joinConditions(service.originalWhere, conditions),
+		placeholders: placeholders,
+	}, nil
 }
 
 func (service *Pagination) replaceOriginalPlaceholders(str string, originalPlaceholders map[string]any, placeholders *[]any) string {
@@ -234,89 +314,156 @@ func (service *Pagination) replaceOriginalPlaceholders(str string, originalPlace
 		return len(keys[i]) > len(keys[j])
 	})
 
-	// Recorremos cada key-value del map
-	for _, key := range keys {
-		// Si encontramos el key en el string
-		for strings.Contains(str, ":"+key) {
-			// Reemplazamos la primera ocurrencia del key por el valor de reemplazo
-			str = strings.Replace(str, ":"+key, service.setPlaceholder(placeholders, originalPlaceholders[key]), 1)
+	// Recorremos el string en orden para que los placeholders posicionales coincidan
+	var builder strings.Builder
+	for i := 0; i < len(str); {
+		if str[i] == ':' {
+			matched := ""
+			for _, key := range keys {
+				if strings.HasPrefix(str[i+1:], key) {
+					matched = key
+					break
+				}
+			}
+			// Reemplazamos la ocurrencia del key por el valor de reemplazo
+			if matched != "" {
+				builder.WriteString(service.setPlaceholder(placeholders, originalPlaceholders[matched]))
+				i += len(matched) + 1
+				continue
+			}
 		}
+		builder.WriteByte(str[i])
+		i++
 	}
-	return str
+	return builder.String()
 }
 
-func (service *Pagination) internalCount(placeholders []any) int {
-	sql := service.getCountSql()
+func (query *query) internalCount() (int, error) {
+	sql := query.getCountSql()
 
 	// Ejecuta la consulta y obtiene el resultado.
 	var count int
-	err := service.db.Raw(sql, placeholders...).Scan(&count).Error
+	err := query.execute(func(db *gorm.DB) error {
+		return db.Raw(sql, query.getArgs()...).Scan(&count).Error
+	})
 	if err != nil {
-		return 0
+		return 0, err
 	}
-	return count
+	return count, nil
 }
 
-func (service *Pagination) getSelectCols(exclusions *[]string) ([]string, []string) {
-	exclusionSet := make(map[string]struct{})
-	if exclusions != nil {
-		for _, excl := range *exclusions {
-			exclusionSet[excl] = struct{}{}
-		}
+// getItemsAndCount obtiene los ítems y, si count es verdadero, el total sin paginar.
+// Si el motor soporta COUNT(*) OVER() el total se lee de la misma consulta.
+func (query *query) getItemsAndCount(cols []string, selectPairs []string, count bool) ([]map[string]any, int, error) {
+	windowCount := count && query.service.dialect.SupportsCountOver()
+	if windowCount {
+		cols = append(cols, countOverAlias)
+		selectPairs = append(selectPairs, "COUNT(*) OVER() as "+countOverAlias)
 	}
 
-	var cols []string
-	var selectPairs []string
-	for colName, colValue := range service.columns {
-		if _, excluded := exclusionSet[colName]; !excluded {
-			cols = append(cols, colName)
-			selectPairs = append(selectPairs, colValue+" as "+colName)
-		}
+	items, err := query.getItems(query.getSql(selectPairs), cols)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	// Si no hay pares seleccionados, incluimos todas las columnas.
-	if len(selectPairs) == 0 {
-		for colName, colValue := range service.columns {
-			cols = append(cols, colName)
-			selectPairs = append(selectPairs, colValue+" as "+colName)
+	if !count {
+		return items, 0, nil
+	}
+
+	if windowCount {
+		if len(items) > 0 {
+			total := toInt(items[0][countOverAlias])
+			for _, item := range items {
+				delete(item, countOverAlias)
+			}
+			return items, total, nil
 		}
+		// Sin filas solo se sabe que el total es 0 si no hubo desplazamiento
+		if query.offset == 0 {
+			return items, 0, nil
+		}
+	}
+
+	total, err := query.internalCount()
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// execute corre fn aplicando el tiempo máximo de la consulta: se cancela el contexto y,
+// si el dialecto lo soporta (p.e. statement_timeout en Postgres), se fija el límite dentro
+// de una transacción para que el servidor también aborte la consulta.
+func (query *query) execute(fn func(db *gorm.DB) error) error {
+	return newQueryError(query.executeWithTimeout(fn))
+}
+
+func (query *query) executeWithTimeout(fn func(db *gorm.DB) error) error {
+	db := query.service.db
+	if query.service.timeout <= 0 {
+		return fn(db)
 	}
 
-	return cols, selectPairs
+	ctx, cancel := context.WithTimeout(db.Statement.Context, query.service.timeout)
+	defer cancel()
+	db = db.WithContext(ctx)
+
+	statement := query.service.dialect.TimeoutStatement(query.service.timeout)
+	if statement == "" {
+		return fn(db)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(statement).Error; err != nil {
+			return err
+		}
+		return fn(tx)
+	})
 }
 
-func (service *Pagination) getSelect2Pairs(valueAttribute, textAttribute string) []string {
-	var selectPairs []string
+// getTimeoutHint devuelve el hint con el tiempo máximo para los motores que lo usan (MySQL)
+func (query *query) getTimeoutHint() string {
+	if query.service.timeout > 0 {
+		return query.service.dialect.TimeoutHint(query.service.timeout)
+	}
+	return ""
+}
 
-	// Agrega el atributo de valor y la etiqueta al slice de selectPairs.
-	if val, ok := service.columns[valueAttribute]; ok {
-		selectPairs = append(selectPairs, fmt.Sprintf("%s as value", val))
+func (service *Pagination) getSelect2Pairs(valueAttribute, textAttribute string) ([]string, error) {
+	// Ambos atributos son obligatorios, sin ellos la consulta no tendría columnas
+	val := service.getColumnDef(valueAttribute)
+	if val == nil || !val.Selectable {
+		return nil, errors.New("attribute value '" + valueAttribute + "' is not allowed")
 	}
-	if lbl, ok := service.columns[textAttribute]; ok {
-		selectPairs = append(selectPairs, fmt.Sprintf("%s as label", lbl))
+	lbl := service.getColumnDef(textAttribute)
+	if lbl == nil || !lbl.Selectable {
+		return nil, errors.New("attribute label '" + textAttribute + "' is not allowed")
 	}
 
-	return selectPairs
+	return []string{
+		fmt.Sprintf("%s as value", val.Expression),
+		fmt.Sprintf("%s as label", lbl.Expression),
+	}, nil
 }
 
-func (service *Pagination) getSql(selectPairs []string) string {
-	sql := "SELECT " +
+func (query *query) getSql(selectPairs []string) string {
+	sql := "SELECT " + query.getTimeoutHint() +
 		strings.Join(selectPairs, ", ") +
-		" FROM " + service.table +
-		" WHERE " + service.where +
-		" " + service.group +
-		" " + service.order +
-		" " + service.offsetLimit
+		" FROM " + query.service.table +
+		" WHERE " + query.where +
+		" " + query.service.group +
+		" " + query.service.dialect.LimitOffset(query.order, query.limit, query.offset)
 
 	return sql
 }
 
-func (service *Pagination) getCountSql() string {
+func (query *query) getCountSql() string {
 	sql := fmt.Sprintf(
-		"SELECT %s FROM %s WHERE %s",
-		service.getColumnCount(),
-		service.table,
-		service.where,
+		"SELECT %s%s FROM %s WHERE %s",
+		query.getTimeoutHint(),
+		query.service.getColumnCount(),
+		query.service.table,
+		query.where,
 	)
 	return sql
 }
@@ -356,7 +503,11 @@ func (service *Pagination) getFilters(filters []request.FilterRequest, condition
 		}
 
 		// Procesamos filtro
-		condition += service.processFilter(filter, condition, placeholders)
+		processed, err := service.processFilter(filter, condition, placeholders)
+		if err != nil {
+			return "", err
+		}
+		condition += processed
 	}
 
 	return condition, nil
@@ -381,6 +532,8 @@ func (service *Pagination) verifyFilterRequest(filter *request.FilterRequest) er
 	// Si no existe el operador, lo seteamos por defecto
 	if filter.Opr != "" {
 		filter.Opr = strings.ToUpper(filter.Opr)
+	} else if filter.Type == "TERM" {
+		filter.Opr = "LIKE"
 	} else {
 		filter.Opr = "="
 	}
@@ -406,7 +559,9 @@ func (service *Pagination) verifyFilterRequest(filter *request.FilterRequest) er
 			"ILIKE",
 		}
 
-		validateOperator(validOperators, filter.Opr)
+		if err := validateOperator(validOperators, filter.Opr); err != nil {
+			return err
+		}
 
 	case "NUMERIC":
 		validOperators := []string{
@@ -418,7 +573,9 @@ func (service *Pagination) verifyFilterRequest(filter *request.FilterRequest) er
 			"<=",
 		}
 
-		validateOperator(validOperators, filter.Opr)
+		if err := validateOperator(validOperators, filter.Opr); err != nil {
+			return err
+		}
 
 	case "TERM":
 		validOperators := []string{
@@ -426,7 +583,29 @@ func (service *Pagination) verifyFilterRequest(filter *request.FilterRequest) er
 			"ILIKE",
 		}
 
-		validateOperator(validOperators, filter.Opr)
+		if err := validateOperator(validOperators, filter.Opr); err != nil {
+			return err
+		}
+	}
+
+	//Validaciones especificas para grupos, los sub-filtros se validan al procesarse
+	if filter.Type == "GROUP" || filter.Type == "NOT_GROUP" {
+		if len(filter.Filters) == 0 {
+			return errors.New("filters cannot be empty")
+		}
+		return nil
+	}
+
+	//Validaciones especificas para subconsultas, el atributo es el nombre registrado
+	if filter.Type == "SUB" {
+		subquery, exists := service.subqueries[filter.Attr]
+		if !exists {
+			return errors.New("subquery filter '" + filter.Attr + "' is not allowed")
+		}
+		if strings.Contains(subquery, ":val") && len(filter.Val) == 0 {
+			return errors.New("val cannot be empty")
+		}
+		return nil
 	}
 
 	//Validaciones especificas para atributo
@@ -436,9 +615,8 @@ func (service *Pagination) verifyFilterRequest(filter *request.FilterRequest) er
 		}
 		// Verificamos si es un valor válido
 		for _, attr := range filter.Attrs {
-			column := service.getColumn(attr)
-			if column == nil {
-				return errors.New("attribute filter '" + attr + "' is not allowed")
+			if err := service.verifyFilterColumn(attr, filter.Type, filter.Opr); err != nil {
+				return err
 			}
 		}
 	} else {
@@ -446,29 +624,31 @@ func (service *Pagination) verifyFilterRequest(filter *request.FilterRequest) er
 			return errors.New("attributes cannot be empty")
 		}
 		// Verificamos si es un valor válido
-		column := service.getColumn(filter.Attr)
-		if column == nil {
-			return errors.New("attribute filter '" + filter.Attr + "' is not allowed")
+		if err := service.verifyFilterColumn(filter.Attr, filter.Type, filter.Opr); err != nil {
+			return err
 		}
 	}
 
 	//Validaciones especificas para el valor
 	switch filter.Type {
-	case "BETWEEN", "DATE_BETWEEN":
+	case "BETWEEN", "NOT_BETWEEN", "DATE_BETWEEN":
 		if len(filter.Vals) != 2 {
 			return errors.New("vals should have two elements")
 		}
-	case "IN":
-		if len(filter.Vals) > 0 {
-			return errors.New("vals cannot be empty")
+	case "NULL", "NOT_NULL":
+		// No requieren valor
+	case "IN", "NOT_IN":
+		// Una lista vacía es válida, IN no devuelve filas y NOT_IN no filtra
+		if len(filter.Vals) > service.maxInValues {
+			return fmt.Errorf("vals cannot have more than %d elements", service.maxInValues)
 		}
 	case "NUMERIC":
 		if !isNumber(filter.Val) {
 			return errors.New("val should be numeric")
 		}
 	case "COLUMN":
-		if service.getColumn(filter.Val) == nil {
-			return errors.New("unknown column '" + filter.Val)
+		if def := service.getColumnDef(filter.Val); def == nil || !def.Filterable {
+			return errors.New("unknown column '" + filter.Val + "'")
 		}
 	default:
 		if len(filter.Val) == 0 {
@@ -479,12 +659,14 @@ func (service *Pagination) verifyFilterRequest(filter *request.FilterRequest) er
 	return nil
 }
 
-func (service *Pagination) processFilter(filter request.FilterRequest, condition string, placeholders *[]any) string {
+func (service *Pagination) processFilter(filter request.FilterRequest, condition string, placeholders *[]any) (string, error) {
 	switch filter.Type {
 	case "SIMPLE":
 		return service.processSimpleOrNumericFilter(filter, condition, placeholders)
 	case "COLUMN":
-		return service.processColumnFilter(filter, condition)
+		return service.processColumnFilter(filter, condition), nil
+	case "SUB":
+		return service.processSubFilter(filter, condition, placeholders), nil
 	case "BETWEEN":
 		return service.processBetweenFilter(filter, false, condition, placeholders)
 	case "NOT_BETWEEN":
@@ -494,52 +676,37 @@ func (service *Pagination) processFilter(filter request.FilterRequest, condition
 	case "NOT_IN":
 		return service.processInFilter(filter, true, condition, placeholders)
 	case "NULL":
-		return service.processNullFilter(filter, false, condition)
+		return service.processNullFilter(filter, false, condition), nil
 	case "NOT_NULL":
-		return service.processNullFilter(filter, true, condition)
+		return service.processNullFilter(filter, true, condition), nil
 	case "TERM":
-		return service.processTermFilter(filter, condition, placeholders)
+		return service.processTermFilter(filter, condition, placeholders), nil
 	case "DATE":
 		return service.processDateFilter(filter, condition, placeholders)
 	case "NUMERIC":
 		return service.processSimpleOrNumericFilter(filter, condition, placeholders)
 	case "DATE_BETWEEN":
 		return service.processDateBetweenFilter(filter, condition, placeholders)
+	case "GROUP", "NOT_GROUP":
+		return service.processGroupFilter(filter, condition, placeholders)
 	default:
-		return "Unknown Filter Type"
+		return "", errors.New("unknown filter type '" + filter.Type + "'")
 	}
 }
 
-func (service *Pagination) getColumn(column string) *string {
-	if val, exists := service.columns[column]; exists {
-		return &val
-	}
-	return nil
-}
+func (service *Pagination) processSimpleOrNumericFilter(filter request.FilterRequest, condition string, placeholders *[]any) (string, error) {
+	column := *service.getColumn(filter.Attr)
 
-func (service *Pagination) getOrder(order types.Order) string {
-	var orderSQL []string
-	for key, value := range order {
-		if col, ok := service.columns[key]; ok {
-			orderSQL = append(orderSQL, col+" "+value)
-		}
-	}
-	if len(orderSQL) > 0 {
-		return "ORDER BY " + strings.Join(orderSQL, ", ")
+	value, err := service.getFilterValue(filter.Attr, filter.Opr, filter.Val)
+	if err != nil {
+		return "", err
 	}
-	return ""
-}
-
-func (service *Pagination) processSimpleOrNumericFilter(filter request.FilterRequest, condition string, placeholders *[]any) string {
-	column := *service.getColumn(filter.Attr)
 
 	return fmt.Sprintf(
-		" %s (%s %s %s)",
+		" %s (%s)",
 		getConn(filter.Conn, condition),
-		column,
-		filter.Opr,
-		service.setPlaceholder(placeholders, filter.Val),
-	)
+		service.getComparison(column, filter.Opr, service.setPlaceholder(placeholders, value)),
+	), nil
 }
 
 func (service *Pagination) processColumnFilter(filter request.FilterRequest, condition string) string {
@@ -548,61 +715,105 @@ func (service *Pagination) processColumnFilter(filter request.FilterRequest, con
 	column2 := *service.getColumn(filter.Val)
 
 	return fmt.Sprintf(
-		" %s (%s %s %s)",
+		" %s (%s)",
 		getConn(filter.Conn, condition),
-		column,
-		filter.Opr,
-		column2,
+		service.getComparison(column, filter.Opr, column2),
 	)
 }
 
-func (service *Pagination) processBetweenFilter(filter request.FilterRequest, not bool, condition string, placeholders *[]any) string {
+// processSubFilter agrega la subconsulta registrada enlazando el valor del filtro en ":val"
+// y los placeholders base del servicio.
+func (service *Pagination) processSubFilter(filter request.FilterRequest, condition string, placeholders *[]any) string {
+	values := map[string]any{"val": filter.Val}
+	for key, value := range service.basePlaceholders {
+		if key != "val" {
+			values[key] = value
+		}
+	}
+
+	subquery := service.replaceOriginalPlaceholders(service.subqueries[filter.Attr], values, placeholders)
+
+	return fmt.Sprintf(" %s (%s)", getConn(filter.Conn, condition), subquery)
+}
+
+func (service *Pagination) processBetweenFilter(filter request.FilterRequest, not bool, condition string, placeholders *[]any) (string, error) {
 
 	// Creamos la columna
 	column := *service.getColumn(filter.Attr)
 
+	from, err := service.getFilterValue(filter.Attr, filter.Opr, filter.Vals[0])
+	if err != nil {
+		return "", err
+	}
+	to, err := service.getFilterValue(filter.Attr, filter.Opr, filter.Vals[1])
+	if err != nil {
+		return "", err
+	}
+
 	conn := getConn(filter.Conn, condition)
 	if not {
 		return fmt.Sprintf(" %s (%s NOT BETWEEN %s AND %s)",
 			conn,
 			column,
-			service.setPlaceholder(placeholders, filter.Vals[0]),
-			service.setPlaceholder(placeholders, filter.Vals[1]),
-		)
+			service.setPlaceholder(placeholders, from),
+			service.setPlaceholder(placeholders, to),
+		), nil
 	} else {
 		return fmt.Sprintf(" %s (%s BETWEEN %s AND %s)",
 			conn,
 			column,
-			service.setPlaceholder(placeholders, filter.Vals[0]),
-			service.setPlaceholder(placeholders, filter.Vals[1]),
-		)
+			service.setPlaceholder(placeholders, from),
+			service.setPlaceholder(placeholders, to),
+		), nil
 	}
 }
 
-func (service *Pagination) processInFilter(filter request.FilterRequest, not bool, condition string, placeholders *[]any) string {
+func (service *Pagination) processInFilter(filter request.FilterRequest, not bool, condition string, placeholders *[]any) (string, error) {
+	conn := getConn(filter.Conn, condition)
 
-	currentPlaceholders := []string{}
+	// Lista vacía: IN nunca se cumple y NOT_IN siempre
+	if len(filter.Vals) == 0 {
+		if not {
+			return fmt.Sprintf(" %s (1 = 1)", conn), nil
+		}
+		return fmt.Sprintf(" %s (1 = 0)", conn), nil
+	}
+
+	values := []any{}
 	for _, val := range filter.Vals {
-		currentPlaceholders = append(currentPlaceholders, service.setPlaceholder(placeholders, val))
+		value, err := service.getFilterValue(filter.Attr, filter.Opr, val)
+		if err != nil {
+			return "", err
+		}
+		values = append(values, value)
 	}
 
 	// Creamos la columna
 	column := *service.getColumn(filter.Attr)
 
-	conn := getConn(filter.Conn, condition)
-	if not {
-		return fmt.Sprintf(" %s (%s NOT IN (%s))",
-			conn,
-			column,
-			currentPlaceholders,
-		)
-	} else {
-		return fmt.Sprintf(" %s (%s IN (%s))",
+	// Las listas grandes se enlazan como un único arreglo si el motor lo permite
+	if comparer, ok := service.dialect.(dialects.ArrayComparer); ok && len(values) >= constants.IN_ARRAY_THRESHOLD {
+		return fmt.Sprintf(" %s (%s)",
 			conn,
-			column,
-			currentPlaceholders,
-		)
+			comparer.InArray(column, service.setPlaceholder(placeholders, toArray(values)), not),
+		), nil
 	}
+
+	currentPlaceholders := []string{}
+	for _, value := range values {
+		currentPlaceholders = append(currentPlaceholders, service.setPlaceholder(placeholders, value))
+	}
+
+	operator := "IN"
+	if not {
+		operator = "NOT IN"
+	}
+	return fmt.Sprintf(" %s (%s %s (%s))",
+		conn,
+		column,
+		operator,
+		strings.Join(currentPlaceholders, ", "),
+	), nil
 }
 
 func (service *Pagination) processNullFilter(filter request.FilterRequest, not bool, condition string) string {
@@ -623,75 +834,88 @@ func (service *Pagination) processTermFilter(filter request.FilterRequest, condi
 	var ors []string
 	for _, attr := range filter.Attrs {
 		column := *service.getColumn(attr)
-		ors = append(ors, fmt.Sprintf("%s %s %s", column, filter.Opr, service.setPlaceholder(placeholders, filter.Val)))
+		ors = append(ors, service.getComparison(column, filter.Opr, service.setPlaceholder(placeholders, filter.Val)))
 	}
 
 	return fmt.Sprintf(" %s (%s)", getConn(filter.Conn, condition), strings.Join(ors, " OR "))
 }
 
+// processGroupFilter procesa recursivamente los sub-filtros y los encierra entre paréntesis.
+func (service *Pagination) processGroupFilter(filter request.FilterRequest, condition string, placeholders *[]any) (string, error) {
+	// Los sub-filtros parten de una condición vacía para que el primero no lleve conector
+	group, err := service.getFilters(filter.Filters, "", placeholders)
+	if err != nil {
+		return "", err
+	}
+
+	if filter.Type == "NOT_GROUP" {
+		// Con CASE una condición desconocida (NULL) cuenta como falsa, así la negación incluye
+		// esas filas en lugar de descartarlas como haría NOT
+		return fmt.Sprintf(" %s (CASE WHEN (%s) THEN 1 ELSE 0 END = 0)", getConn(filter.Conn, condition), strings.TrimSpace(group)), nil
+	}
+	return fmt.Sprintf(" %s (%s)", getConn(filter.Conn, condition), strings.TrimSpace(group)), nil
+}
+
 // Creamos la columna
-func (service *Pagination) processDateFilter(filter request.FilterRequest, condition string, placeholders *[]any) string {
+func (service *Pagination) processDateFilter(filter request.FilterRequest, condition string, placeholders *[]any) (string, error) {
 	column := *service.getColumn(filter.Attr)
 
-	conn := getConn(filter.Conn, condition)
-	valPlaceholder := service.setPlaceholder(placeholders, filter.Val)
+	value, err := service.getDateFilterValue(filter.Attr, filter.Val)
+	if err != nil {
+		return "", err
+	}
 
-	switch getDatabaseType(service.db) {
-	case "mysql":
-		return fmt.Sprintf(" %s (%s BETWEEN %s AND DATE_ADD(%s, INTERVAL 1 DAY))",
-			conn,
-			column,
-			valPlaceholder,
-			valPlaceholder,
-		)
-	case "postgres":
-		fallthrough
-	default:
-		return fmt.Sprintf(" %s (%s BETWEEN (%s)::TIMESTAMP AND (%s)::TIMESTAMP + interval '1 days')",
-			conn,
+	// El valor se enlaza dos veces porque algunos motores usan placeholders posicionales
+	return fmt.Sprintf(" %s (%s)",
+		getConn(filter.Conn, condition),
+		service.dialect.DateRange(
 			column,
-			valPlaceholder,
-			valPlaceholder,
-		)
-	}
+			service.setPlaceholder(placeholders, value),
+			service.setPlaceholder(placeholders, value),
+		),
+	), nil
 }
 
-func (service *Pagination) processDateBetweenFilter(filter request.FilterRequest, condition string, placeholders *[]any) string {
+func (service *Pagination) processDateBetweenFilter(filter request.FilterRequest, condition string, placeholders *[]any) (string, error) {
 
 	column := *service.getColumn(filter.Attr)
 
-	switch getDatabaseType(service.db) {
-	case "mysql":
-		return fmt.Sprintf(" %s (%s BETWEEN %s AND DATE_ADD(%s, INTERVAL 1 DAY))",
-			getConn(filter.Conn, condition),
-			column,
-			service.setPlaceholder(placeholders, filter.Vals[0]),
-			service.setPlaceholder(placeholders, filter.Vals[1]),
-		)
-	case "postgres":
-		fallthrough
-	default:
-		return fmt.Sprintf(" %s (%s BETWEEN (%s)::TIMESTAMP AND (%s)::TIMESTAMP + interval '1 days')",
-			getConn(filter.Conn, condition),
+	from, err := service.getDateFilterValue(filter.Attr, filter.Vals[0])
+	if err != nil {
+		return "", err
+	}
+	to, err := service.getDateFilterValue(filter.Attr, filter.Vals[1])
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(" %s (%s)",
+		getConn(filter.Conn, condition),
+		service.dialect.DateRange(
 			column,
-			service.setPlaceholder(placeholders, filter.Vals[0]),
-			service.setPlaceholder(placeholders, filter.Vals[1]),
-		)
+			service.setPlaceholder(placeholders, from),
+			service.setPlaceholder(placeholders, to),
+		),
+	), nil
+}
+
+// getComparison arma "left opr right", ILIKE se delega al dialecto porque no todos los motores lo soportan.
+// En LIKE e ILIKE "\" escapa los comodines en todos los motores.
+func (service *Pagination) getComparison(left string, opr string, right string) string {
+	switch opr {
+	case "ILIKE":
+		return service.dialect.CaseInsensitiveLike(left, right) + service.dialect.LikeEscape()
+	case "LIKE":
+		return left + " " + opr + " " + right + service.dialect.LikeEscape()
 	}
+	return left + " " + opr + " " + right
 }
 
 func (service *Pagination) setPlaceholder(placeholders *[]any, value any) string {
 
 	*placeholders = append(*placeholders, value)
 
-	switch getDatabaseType(service.db) {
-	case "mysql":
-		return "?"
-	case "postgres":
-		fallthrough
-	default:
-		return "$" + strconv.Itoa(len(*placeholders))
-	}
+	return service.dialect.Placeholder(len(*placeholders))
 }
 
 // func (service *Pagination) setPlaceholder(placeholders *[]any, value string) string {
@@ -706,40 +930,82 @@ func (service *Pagination) setPlaceholder(placeholders *[]any, value any) string
 // 	return "@" + key
 // }
 
-func (service *Pagination) getItems(sql string, cols []string, placeholders []any) ([]map[string]any, error) {
+// getArgs devuelve los placeholders en la forma que espera el dialecto
+func (query *query) getArgs() []any {
+	return bindArgs(query.service.dialect, query.placeholders)
+}
 
-	rows, err := service.db.Raw(sql, placeholders...).Rows()
+func bindArgs(dialect dialects.Dialect, args []any) []any {
+	if binder, ok := dialect.(dialects.ArgsBinder); ok {
+		return binder.BindArgs(args)
+	}
+	return args
+}
+
+func (query *query) getItems(sql string, cols []string) ([]map[string]any, error) {
+	items, columnTypes, err := query.getRawItems(sql, cols)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+
+	if err := query.service.normalizeItems(items, cols, columnTypes); err != nil {
+		return nil, newQueryError(err)
+	}
+	return items, nil
+}
+
+// getRawItems devuelve las filas tal como las entrega el driver junto con los tipos de sus columnas
+func (query *query) getRawItems(statement string, cols []string) ([]map[string]any, []*sql.ColumnType, error) {
 
 	items := []map[string]any{}
-	for rows.Next() {
-		columns := make([]any, len(cols))
-		columnPointers := make([]any, len(cols))
-		for i := range columns {
-			columnPointers[i] = &columns[i]
+	var columnTypes []*sql.ColumnType
+	err := query.execute(func(db *gorm.DB) error {
+		rows, err := db.Raw(statement, query.getArgs()...).Rows()
+		if err != nil {
+			return err
 		}
+		defer rows.Close()
 
-		if err := rows.Scan(columnPointers...); err != nil {
-			return nil, err
+		columnTypes, err = rows.ColumnTypes()
+		if err != nil {
+			return err
 		}
 
-		row := make(map[string]any)
-		for i, colName := range cols {
-			val := columnPointers[i].(*any)
-			row[colName] = *val
+		for rows.Next() {
+			row, err := scanRow(rows, cols)
+			if err != nil {
+				return err
+			}
+
+			items = append(items, row)
 		}
 
-		items = append(items, row)
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return items, columnTypes, nil
+}
+
+// scanRow lee la fila actual en un mapa alias => valor
+func scanRow(rows *sql.Rows, cols []string) (map[string]any, error) {
+	columns := make([]any, len(cols))
+	columnPointers := make([]any, len(cols))
+	for i := range columns {
+		columnPointers[i] = &columns[i]
 	}
 
-	if err := rows.Err(); err != nil {
+	if err := rows.Scan(columnPointers...); err != nil {
 		return nil, err
 	}
 
-	return items, nil
+	row := make(map[string]any, len(cols))
+	for i, colName := range cols {
+		row[colName] = columns[i]
+	}
+	return row, nil
 }
 
 // Otras funciones
@@ -750,6 +1016,15 @@ func validateOperator(validOperators []string, opr string) error {
 	return nil
 }
 
+// joinConditions une dos condiciones con AND encerrando cada una entre paréntesis,
+// si condition está vacía devuelve where sin cambios
+func joinConditions(where string, condition string) string {
+	if condition = strings.TrimSpace(condition); condition == "" {
+		return where
+	}
+	return "(" + where + ") AND (" + condition + ")"
+}
+
 func getConn(conn string, condition string) string {
 	if strings.TrimSpace(condition) != "" {
 		return conn
@@ -757,14 +1032,14 @@ func getConn(conn string, condition string) string {
 	return ""
 }
 
-func getLimit(findRequest request.FindRequest) string {
+func getLimit(findRequest request.FindRequest) int {
 	if findRequest.Limit > 0 {
-		return "LIMIT " + strconv.Itoa(findRequest.Limit)
+		return findRequest.Limit
 	}
-	return ""
+	return 0
 }
 
-func getInfiniteScroll(infiniteScroll request.InfiniteScrollRequest) string {
+func getInfiniteScroll(infiniteScroll request.InfiniteScrollRequest) (int, int) {
 	// Asignar valores predeterminados si son cero o negativos
 	if infiniteScroll.Limit <= 0 {
 		infiniteScroll.Limit = 10
@@ -776,14 +1051,10 @@ func getInfiniteScroll(infiniteScroll request.InfiniteScrollRequest) string {
 	// Calcular el OFFSET
 	offset := (infiniteScroll.Page - 1) * infiniteScroll.Limit
 
-	// Construir la cláusula LIMIT y OFFSET
-	if infiniteScroll.Limit > 0 {
-		return fmt.Sprintf("LIMIT %d OFFSET %d", infiniteScroll.Limit, offset)
-	}
-	return ""
+	return infiniteScroll.Limit, offset
 }
 
-func getPagination(pagination request.PaginationRequest) string {
+func getPagination(pagination request.PaginationRequest) (int, int) {
 	// Aplicar valores predeterminados si no están definidos
 	if pagination.Limit <= 0 {
 		pagination.Limit = 10
@@ -795,14 +1066,10 @@ func getPagination(pagination request.PaginationRequest) string {
 	// Calcular el offset
 	offset := (pagination.Page - 1) * pagination.Limit
 
-	// Construir la cláusula LIMIT y OFFSET
-	if pagination.Limit > 0 {
-		return fmt.Sprintf("LIMIT %d OFFSET %d", pagination.Limit, offset)
-	}
-	return ""
+	return pagination.Limit, offset
 }
 
-func getPaginationOffset(pagination request.PaginationOffsetRequest) string {
+func getPaginationOffset(pagination request.PaginationOffsetRequest) (int, int) {
 	// Establece valores predeterminados si no se proporcionan
 	if pagination.Limit == 0 {
 		pagination.Limit = 10
@@ -812,16 +1079,7 @@ func getPaginationOffset(pagination request.PaginationOffsetRequest) string {
 		pagination.Offset = 0
 	}
 
-	var parts []string
-	if pagination.Limit > 0 {
-		parts = append(parts, fmt.Sprintf("LIMIT %d", pagination.Limit))
-	}
-
-	if pagination.Offset > 0 {
-		parts = append(parts, fmt.Sprintf("OFFSET %d", pagination.Offset))
-	}
-
-	return strings.Join(parts, " ")
+	return pagination.Limit, pagination.Offset
 }
 
 func isNumber(s string) bool {
@@ -829,13 +1087,24 @@ func isNumber(s string) bool {
 	return err == nil
 }
 
-func getDatabaseType(db *gorm.DB) string {
-	switch db.Dialector.(type) {
-	case *mysql.Dialector:
-		return "mysql"
-	case *postgres.Dialector:
-		return "postgres"
+// toInt convierte el resultado de un COUNT, cuyo tipo depende del driver
+func toInt(value any) int {
+	switch v := value.(type) {
+	case int64:
+		return int(v)
+	case int32:
+		return int(v)
+	case int:
+		return v
+	case float64:
+		return int(v)
+	case []byte:
+		i, _ := strconv.Atoi(string(v))
+		return i
+	case string:
+		i, _ := strconv.Atoi(v)
+		return i
 	default:
-		return "unknown"
+		return 0
 	}
 }
@@ -1,14 +1,24 @@
 package services_test
 
 import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/devsstudio/gosql/constants"
 	"github.com/devsstudio/gosql/request"
 	"github.com/devsstudio/gosql/services"
 	"github.com/devsstudio/gosql/types"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
@@ -26,6 +36,29 @@ func setupMockDB() (*gorm.DB, sqlmock.Sqlmock, error) {
 	return gormDB, mock, err
 }
 
+// arrayConverter deja pasar los slices como lo hace el driver de Postgres para los arreglos
+type arrayConverter struct{}
+
+func (arrayConverter) ConvertValue(value any) (driver.Value, error) {
+	if kind := reflect.TypeOf(value); kind != nil && kind.Kind() == reflect.Slice && kind.Elem().Kind() != reflect.Uint8 {
+		return value, nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(value)
+}
+
+func setupPostgresMockDB() (*gorm.DB, sqlmock.Sqlmock, error) {
+	db, mock, err := sqlmock.New(sqlmock.ValueConverterOption(arrayConverter{}))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{
+		Conn: db,
+	}), &gorm.Config{})
+
+	return gormDB, mock, err
+}
+
 func TestPaginationService_FindAll(t *testing.T) {
 	db, mock, err := setupMockDB()
 	assert.NoError(t, err)
@@ -90,6 +123,27 @@ func TestPaginationService_FindSelect2(t *testing.T) {
 	assert.Equal(t, "John Doe", select2Resp.Items[0]["label"])
 }
 
+func TestPaginationService_FindSelect2Invalid(t *testing.T) {
+	db, _, err := setupMockDB()
+	assert.NoError(t, err)
+
+	baseParams := types.ListParams{
+		Table: "users",
+		ColumnDefs: []types.ColumnDef{
+			types.NewColumnDef("id", "ID", types.ColumnTypeInt),
+			{Name: "password", Expression: "Password", Filterable: true},
+		},
+	}
+	paginationService := services.PaginationService(db, baseParams)
+
+	// Se valida antes de ejecutar la consulta
+	_, err = paginationService.FindSelect2([]request.FilterRequest{}, request.InfiniteScrollRequest{}, "missing", "id")
+	assert.EqualError(t, err, "attribute value 'missing' is not allowed")
+
+	_, err = paginationService.FindSelect2([]request.FilterRequest{}, request.InfiniteScrollRequest{}, "id", "password")
+	assert.EqualError(t, err, "attribute label 'password' is not allowed")
+}
+
 func TestPaginationService_FindPaginated(t *testing.T) {
 	db, mock, err := setupMockDB()
 	assert.NoError(t, err)
@@ -123,3 +177,372 @@ func TestPaginationService_FindPaginated(t *testing.T) {
 	assert.Equal(t, 1, paginatedResp.TotalPages)
 	assert.Equal(t, 0, paginatedResp.TotalItems)
 }
+
+func TestPaginationService_GroupFilters(t *testing.T) {
+	db, mock, err := setupMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	columns := types.Columns{"status": "u.status", "owner": "u.owner_id"}
+	baseParams := types.ListParams{
+		Table:   "users u",
+		Columns: columns,
+	}
+
+	paginationService := services.PaginationService(db, baseParams)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users u WHERE (1 = 1) AND (((u.status = ?) OR ((u.status = ?) AND (u.owner_id <> ?))) AND (u.owner_id = ?))")).
+		WithArgs("A", "B", "7", "5").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	filters := []request.FilterRequest{
+		{
+			Type: "group",
+			Filters: []request.FilterRequest{
+				{Attr: "status", Val: "A"},
+				{
+					Type: "GROUP",
+					Conn: "or",
+					Filters: []request.FilterRequest{
+						{Attr: "status", Val: "B"},
+						{Attr: "owner", Opr: "<>", Val: "7"},
+					},
+				},
+			},
+		},
+		{Attr: "owner", Val: "5"},
+	}
+	count, err := paginationService.Count(filters)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPaginationService_GroupFiltersValidation(t *testing.T) {
+	db, _, err := setupMockDB()
+	assert.NoError(t, err)
+
+	columns := types.Columns{"status": "u.status"}
+	paginationService := services.PaginationService(db, types.ListParams{Table: "users u", Columns: columns})
+
+	_, err = paginationService.Count([]request.FilterRequest{{Type: "GROUP"}})
+	assert.EqualError(t, err, "filters cannot be empty")
+
+	_, err = paginationService.Count([]request.FilterRequest{
+		{Type: "GROUP", Filters: []request.FilterRequest{
+			{Type: "GROUP", Filters: []request.FilterRequest{{Attr: "unknown", Val: "A"}}},
+		}},
+	})
+	assert.EqualError(t, err, "attribute filter 'unknown' is not allowed")
+}
+
+func TestPaginationService_SubFilter(t *testing.T) {
+	db, mock, err := setupMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	columns := types.Columns{"id": "u.id", "name": "u.name"}
+	baseParams := types.ListParams{
+		Table:        "users u",
+		Columns:      columns,
+		Placeholders: map[string]any{"tenant": 9},
+		Subqueries: types.Subqueries{
+			"has_open_orders": "EXISTS (SELECT 1 FROM orders o WHERE o.user_id = u.id AND o.status = :val AND o.tenant_id = :tenant)",
+			"is_admin":        "EXISTS (SELECT 1 FROM admins a WHERE a.user_id = u.id)",
+		},
+	}
+
+	paginationService := services.PaginationService(db, baseParams)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users u WHERE (1 = 1) AND ((u.name = ?) AND (EXISTS (SELECT 1 FROM orders o WHERE o.user_id = u.id AND o.status = ? AND o.tenant_id = ?)) OR (EXISTS (SELECT 1 FROM admins a WHERE a.user_id = u.id)))")).
+		WithArgs("John", "OPEN", 9).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	filters := []request.FilterRequest{
+		{Attr: "name", Val: "John"},
+		{Type: "SUB", Attr: "has_open_orders", Val: "OPEN"},
+		{Type: "SUB", Attr: "is_admin", Conn: "OR"},
+	}
+	count, err := paginationService.Count(filters)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = paginationService.Count([]request.FilterRequest{{Type: "SUB", Attr: "unknown"}})
+	assert.EqualError(t, err, "subquery filter 'unknown' is not allowed")
+
+	_, err = paginationService.Count([]request.FilterRequest{{Type: "SUB", Attr: "has_open_orders"}})
+	assert.EqualError(t, err, "val cannot be empty")
+}
+
+func TestPaginationService_ConcurrentQueries(t *testing.T) {
+	db, mock, err := setupMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+	mock.MatchExpectationsInOrder(false)
+
+	where := "tenant_id = :tenant"
+	baseParams := types.ListParams{
+		Table:        "users",
+		Columns:      types.Columns{"id": "id", "name": "name"},
+		Where:        &where,
+		Placeholders: map[string]any{"tenant": 1},
+	}
+
+	// Un solo servicio compartido por todas las goroutines
+	paginationService := services.PaginationService(db, baseParams)
+
+	const workers = 20
+	for i := 0; i < workers; i++ {
+		name := fmt.Sprintf("user-%d", i)
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id as id, name as name FROM users WHERE (tenant_id = ?) AND ((name = ?))  ORDER BY name ASC LIMIT 5")).
+			WithArgs(1, name).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(i, name))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users WHERE (tenant_id = ?) AND ((id = ?))")).
+			WithArgs(1, fmt.Sprint(i)).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(i))
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			name := fmt.Sprintf("user-%d", i)
+			items, err := paginationService.FindAll(
+				[]request.FilterRequest{{Attr: "name", Val: name}},
+				request.FindRequest{Limit: 5, Order: types.Order{{Column: "name", Direction: "asc"}}},
+				nil,
+			)
+			assert.NoError(t, err)
+			if assert.Equal(t, 1, len(items)) {
+				assert.Equal(t, name, items[0]["name"])
+			}
+
+			count, err := paginationService.Count([]request.FilterRequest{{Attr: "id", Val: fmt.Sprint(i)}})
+			assert.NoError(t, err)
+			assert.Equal(t, i, count)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPaginationService_BaseWhereWithOr(t *testing.T) {
+	db, mock, err := setupMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	where := "tenant_id = :tenant"
+	baseParams := types.ListParams{
+		Table:        "users",
+		Columns:      types.Columns{"id": "id", "name": "name"},
+		Where:        &where,
+		Placeholders: map[string]any{"tenant": 1},
+	}
+
+	paginationService := services.PaginationService(db, baseParams)
+
+	// Un conector OR del cliente no puede escapar de la condición base
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users WHERE (tenant_id = ?) AND ((name = ?) OR (name = ?))")).
+		WithArgs(1, "zzz", "c").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	count, err := paginationService.Count([]request.FilterRequest{
+		{Attr: "name", Val: "zzz", Conn: "OR"},
+		{Attr: "name", Val: "c", Conn: "OR"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPaginationService_FindPaginatedOffset(t *testing.T) {
+	db, mock, err := setupMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	where := "tenant_id = :tenant"
+	baseParams := types.ListParams{
+		Table:        "users",
+		Columns:      types.Columns{"id": "id", "name": "name"},
+		Where:        &where,
+		Placeholders: map[string]any{"tenant": 1},
+	}
+
+	paginationService := services.PaginationService(db, baseParams)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users WHERE tenant_id = ?")).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(10))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id as id, name as name FROM users WHERE (tenant_id = ?) AND ((name = ?))   LIMIT 10 OFFSET 20")).
+		WithArgs(1, "John").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "John"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users WHERE (tenant_id = ?) AND ((name = ?))")).
+		WithArgs(1, "John").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	offsetResp, err := paginationService.FindPaginatedOffset(
+		[]request.FilterRequest{{Attr: "name", Val: "John"}},
+		request.PaginationOffsetRequest{Offset: 20, Limit: 10},
+		nil,
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 10, offsetResp.TotalItems)
+	assert.Equal(t, 1, offsetResp.FilteredItems)
+	assert.Equal(t, 1, len(offsetResp.Items))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPaginationService_WithContext(t *testing.T) {
+	db, _, err := setupMockDB()
+	assert.NoError(t, err)
+
+	paginationService := services.PaginationService(db, types.ListParams{Table: "users", Columns: types.Columns{"id": "id"}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = paginationService.WithContext(ctx).FindAll([]request.FilterRequest{}, request.FindRequest{}, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = paginationService.WithContext(ctx).Count([]request.FilterRequest{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestPaginationService_TimeoutMySQL(t *testing.T) {
+	db, mock, err := setupMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	baseParams := types.ListParams{
+		Table:   "users",
+		Columns: types.Columns{"id": "id"},
+		Timeout: 2 * time.Second,
+	}
+	paginationService := services.PaginationService(db, baseParams)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT /*+ MAX_EXECUTION_TIME(2000) */ COUNT(*) FROM users WHERE 1 = 1")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+	count, err := paginationService.Count([]request.FilterRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count)
+
+	// Se cancela la consulta cuando supera el tiempo máximo
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT /*+ MAX_EXECUTION_TIME(50) */ id as id FROM users WHERE 1 = 1")).
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	_, err = paginationService.WithTimeout(50*time.Millisecond).FindAll([]request.FilterRequest{}, request.FindRequest{}, nil)
+	assert.Error(t, err)
+}
+
+func TestPaginationService_TimeoutPostgres(t *testing.T) {
+	db, mock, err := setupPostgresMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	baseParams := types.ListParams{
+		Table:   "users",
+		Columns: types.Columns{"id": "id", "name": "name"},
+		Timeout: 1500 * time.Millisecond,
+	}
+	paginationService := services.PaginationService(db, baseParams)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("SET LOCAL statement_timeout = 1500")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users WHERE (1 = 1) AND ((name = $1))")).
+		WithArgs("John").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectCommit()
+
+	count, err := paginationService.Count([]request.FilterRequest{{Attr: "name", Val: "John"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPaginationService_InFilters(t *testing.T) {
+	db, mock, err := setupMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	baseParams := types.ListParams{
+		Table:       "users",
+		ColumnDefs:  []types.ColumnDef{types.NewColumnDef("id", "id", types.ColumnTypeInt)},
+		MaxInValues: 3,
+	}
+	paginationService := services.PaginationService(db, baseParams)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users WHERE (1 = 1) AND ((id IN (?, ?, ?)) AND (id NOT IN (?)) AND (1 = 0) OR (1 = 1))")).
+		WithArgs(int64(1), int64(2), int64(3), int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	count, err := paginationService.Count([]request.FilterRequest{
+		{Type: "IN", Attr: "id", Vals: []string{"1", "2", "3"}},
+		{Type: "NOT_IN", Attr: "id", Vals: []string{"2"}},
+		{Type: "IN", Attr: "id"},
+		{Type: "NOT_IN", Attr: "id", Conn: "OR"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	_, err = paginationService.Count([]request.FilterRequest{{Type: "NOT_IN", Attr: "id", Vals: []string{"1", "2", "3", "4"}}})
+	assert.EqualError(t, err, "vals cannot have more than 3 elements")
+
+	_, err = paginationService.Count([]request.FilterRequest{{Type: "IN", Attr: "id", Vals: []string{"1", "x"}}})
+	assert.Error(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPaginationService_InFiltersPostgresArray(t *testing.T) {
+	db, mock, err := setupPostgresMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	baseParams := types.ListParams{
+		Table: "users",
+		ColumnDefs: []types.ColumnDef{
+			types.NewColumnDef("id", "id", types.ColumnTypeInt),
+			types.NewColumnDef("code", "code", types.ColumnTypeText),
+		},
+	}
+	paginationService := services.PaginationService(db, baseParams)
+
+	ids, codes := []string{}, []string{}
+	expectedIds := []int64{}
+	for i := 1; i <= constants.IN_ARRAY_THRESHOLD; i++ {
+		ids = append(ids, strconv.Itoa(i))
+		codes = append(codes, "C"+strconv.Itoa(i))
+		expectedIds = append(expectedIds, int64(i))
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users WHERE (1 = 1) AND ((id = ANY($1)) AND (code <> ALL($2)) AND (id IN ($3, $4)))")).
+		WithArgs(expectedIds, codes, int64(1), int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	_, err = paginationService.Count([]request.FilterRequest{
+		{Type: "IN", Attr: "id", Vals: ids},
+		{Type: "NOT_IN", Attr: "code", Vals: codes},
+		{Type: "IN", Attr: "id", Vals: []string{"1", "2"}},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
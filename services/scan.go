@@ -0,0 +1,258 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/response"
+)
+
+var (
+	timeType    = reflect.TypeOf(time.Time{})
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+// FindAllAs ejecuta FindAll y convierte cada fila en un T. Los alias se asignan a los campos
+// por la etiqueta `db`, luego por `json` y por último por el nombre del campo.
+func FindAllAs[T any](service *Pagination, filters []request.FilterRequest, findRequest request.FindRequest, exclusions *[]string) ([]T, error) {
+	items, err := service.FindAll(filters, findRequest, exclusions)
+	if err != nil {
+		return nil, err
+	}
+	return scanItems[T](items)
+}
+
+// FindPaginatedAs ejecuta FindPaginated y convierte cada fila en un T, ver FindAllAs
+func FindPaginatedAs[T any](service *Pagination, filters []request.FilterRequest, pagination request.PaginationRequest, exclusions *[]string) (*response.TypedPaginationResponse[T], error) {
+	paginated, err := service.FindPaginated(filters, pagination, exclusions)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := scanItems[T](paginated.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.TypedPaginationResponse[T]{
+		Page:       paginated.Page,
+		Limit:      paginated.Limit,
+		TotalPages: paginated.TotalPages,
+		TotalItems: paginated.TotalItems,
+		Items:      items,
+		Aggregates: paginated.Aggregates,
+	}, nil
+}
+
+func scanItems[T any](items []map[string]any) ([]T, error) {
+	itemType := reflect.TypeOf((*T)(nil)).Elem()
+	if itemType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot scan into %s, a struct is required", itemType)
+	}
+	fields := getStructFields(itemType)
+
+	result := make([]T, len(items))
+	for i, item := range items {
+		target := reflect.ValueOf(&result[i]).Elem()
+		for alias, value := range item {
+			index, exists := fields[alias]
+			if !exists {
+				continue
+			}
+			field := target.FieldByIndex(index)
+			if err := assignValue(field, value); err != nil {
+				return nil, fmt.Errorf("attribute '%s': %w", alias, err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// getStructFields relaciona cada alias con el índice del campo, incluyendo los de structs embebidos
+func getStructFields(structType reflect.Type) map[string][]int {
+	fields := map[string][]int{}
+	for _, field := range reflect.VisibleFields(structType) {
+		if !field.IsExported() || (field.Anonymous && field.Type.Kind() == reflect.Struct) {
+			continue
+		}
+
+		name := field.Name
+		if tag := tagName(field.Tag.Get("json")); tag != "" {
+			name = tag
+		}
+		if tag := tagName(field.Tag.Get("db")); tag != "" {
+			name = tag
+		}
+		if name == "-" {
+			continue
+		}
+		// Un campo promovido no reemplaza a uno declarado con el mismo alias
+		if _, exists := fields[name]; !exists || len(field.Index) == 1 {
+			fields[name] = field.Index
+		}
+	}
+	return fields
+}
+
+func tagName(tag string) string {
+	name, _, _ := strings.Cut(tag, ",")
+	return name
+}
+
+// assignValue convierte el valor devuelto por el driver al tipo del campo
+func assignValue(field reflect.Value, value any) error {
+	if value == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	if field.Addr().Type().Implements(scannerType) {
+		return field.Addr().Interface().(sql.Scanner).Scan(value)
+	}
+
+	if field.Kind() == reflect.Pointer {
+		elem := reflect.New(field.Type().Elem())
+		if err := assignValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+
+	// Los drivers devuelven texto como []byte, salvo que el campo sea justamente []byte
+	if bytes, ok := value.([]byte); ok {
+		if field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.Uint8 {
+			field.SetBytes(append([]byte(nil), bytes...))
+			return nil
+		}
+		value = string(bytes)
+	}
+
+	source := reflect.ValueOf(value)
+	if source.Type().AssignableTo(field.Type()) {
+		field.Set(source)
+		return nil
+	}
+
+	// Los decimales y JSON normalizados se convierten como texto
+	switch v := value.(type) {
+	case json.Number:
+		value = string(v)
+		source = reflect.ValueOf(value)
+	case json.RawMessage:
+		value = string(v)
+		source = reflect.ValueOf(value)
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		switch v := value.(type) {
+		case time.Time:
+			field.SetString(v.Format(time.RFC3339Nano))
+		default:
+			field.SetString(fmt.Sprint(v))
+		}
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number, err := toInt64(value)
+		if err != nil || field.OverflowInt(number) {
+			return cannotAssign(value, field)
+		}
+		field.SetInt(number)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		number, err := toInt64(value)
+		if err != nil || number < 0 || field.OverflowUint(uint64(number)) {
+			return cannotAssign(value, field)
+		}
+		field.SetUint(uint64(number))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		number, err := toFloat64(value)
+		if err != nil || field.OverflowFloat(number) {
+			return cannotAssign(value, field)
+		}
+		field.SetFloat(number)
+		return nil
+	case reflect.Bool:
+		switch v := value.(type) {
+		case int64:
+			field.SetBool(v != 0)
+		case string:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return cannotAssign(value, field)
+			}
+			field.SetBool(b)
+		default:
+			return cannotAssign(value, field)
+		}
+		return nil
+	case reflect.Struct, reflect.Map, reflect.Slice:
+		text, ok := value.(string)
+		if !ok {
+			break
+		}
+		// Las fechas guardadas como texto (SQLite, DSN sin parseTime) y las columnas JSON
+		if field.Type() == timeType {
+			t, err := parseTime(text, false)
+			if err != nil {
+				return cannotAssign(value, field)
+			}
+			field.Set(reflect.ValueOf(t))
+			return nil
+		}
+		if err := json.Unmarshal([]byte(text), field.Addr().Interface()); err != nil {
+			return cannotAssign(value, field)
+		}
+		return nil
+	}
+
+	if source.Type().ConvertibleTo(field.Type()) {
+		field.Set(source.Convert(field.Type()))
+		return nil
+	}
+	return cannotAssign(value, field)
+}
+
+func toInt64(value any) (int64, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case float64:
+		if v != float64(int64(v)) {
+			return 0, fmt.Errorf("%v is not an integer", v)
+		}
+		return int64(v), nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		return strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+	}
+	return 0, fmt.Errorf("%T is not a number", value)
+}
+
+func toFloat64(value any) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case int64:
+		return float64(v), nil
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(v), 64)
+	}
+	return 0, fmt.Errorf("%T is not a number", value)
+}
+
+func cannotAssign(value any, field reflect.Value) error {
+	return fmt.Errorf("cannot assign %T %v to %s", value, value, field.Type())
+}
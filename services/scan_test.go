@@ -0,0 +1,98 @@
+package services_test
+
+import (
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/services"
+	"github.com/devsstudio/gosql/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type auditFields struct {
+	Created time.Time `json:"created"`
+}
+
+type userItem struct {
+	auditFields
+	ID       int            `db:"id"`
+	Name     string         `json:"name,omitempty"`
+	Score    *float64       `json:"score"`
+	Active   bool           `json:"active"`
+	Email    sql.NullString `json:"email"`
+	Tags     []string       `json:"tags"`
+	Ignored  string         `json:"-"`
+	Nickname string
+}
+
+func TestFindAs(t *testing.T) {
+	db, mock, err := setupMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	baseParams := types.ListParams{
+		Table: "users",
+		Columns: types.Columns{
+			"id":       "id",
+			"name":     "name",
+			"score":    "score",
+			"active":   "active",
+			"email":    "email",
+			"tags":     "tags",
+			"created":  "created_at",
+			"Nickname": "nickname",
+		},
+	}
+	paginationService := services.PaginationService(db, baseParams)
+
+	created := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	columns := []string{"Nickname", "active", "created", "email", "id", "name", "score", "tags"}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT nickname as Nickname, active as active, created_at as created, email as email, id as id, name as name, score as score, tags as tags FROM users WHERE 1 = 1  ORDER BY id ASC LIMIT 2")).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow([]byte("Jo"), int64(1), created, []byte("jo@mail.com"), int64(1), []byte("John"), []byte("7.5"), []byte(`["a","b"]`)).
+			AddRow(nil, int64(0), []byte("2024-01-16 08:00:00"), nil, int64(2), []byte("Jane"), nil, nil))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users WHERE 1 = 1")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	paginated, err := services.FindPaginatedAs[userItem](paginationService, []request.FilterRequest{}, request.PaginationRequest{
+		Limit: 2,
+		Count: true,
+		Order: types.Order{{Column: "id", Direction: "asc"}},
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, paginated.TotalItems)
+
+	score := 7.5
+	assert.Equal(t, []userItem{
+		{
+			auditFields: auditFields{Created: created},
+			ID:          1,
+			Name:        "John",
+			Score:       &score,
+			Active:      true,
+			Email:       sql.NullString{String: "jo@mail.com", Valid: true},
+			Tags:        []string{"a", "b"},
+			Nickname:    "Jo",
+		},
+		{
+			auditFields: auditFields{Created: time.Date(2024, 1, 16, 8, 0, 0, 0, time.UTC)},
+			ID:          2,
+			Name:        "Jane",
+		},
+	}, paginated.Items)
+
+	// Valor que no corresponde al tipo del campo
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id as id FROM users WHERE 1 = 1")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow([]byte("x")))
+
+	_, err = services.FindAllAs[userItem](paginationService, []request.FilterRequest{}, request.FindRequest{}, &[]string{"Nickname", "active", "created", "email", "name", "score", "tags"})
+	assert.EqualError(t, err, "attribute 'id': cannot assign string x to int")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,321 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/devsstudio/gosql/internal/testdb"
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/response"
+	"github.com/devsstudio/gosql/services"
+	"github.com/devsstudio/gosql/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupSQLiteDB(t *testing.T) *gorm.DB {
+	return testdb.Open(t,
+		`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, email TEXT, score INTEGER, min_score INTEGER, created_at DATETIME)`,
+		`CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER, status TEXT)`,
+		`INSERT INTO users VALUES (1, 'John', 'JOHN@MAIL.COM', 10, 5, '2024-01-15 10:30:00')`,
+		`INSERT INTO users VALUES (2, 'Jane', 'jane@mail.com', 20, 25, '2024-01-16 08:00:00')`,
+		`INSERT INTO users VALUES (3, 'Bob', NULL, 30, 10, '2024-02-01 23:59:00')`,
+		`INSERT INTO users VALUES (4, 'Alice', 'alice@mail.com', NULL, 0, '2024-03-10 12:00:00')`,
+		`INSERT INTO orders VALUES (1, 1, 'OPEN'), (2, 3, 'CLOSED'), (3, 3, 'OPEN')`,
+	)
+}
+
+func newSQLiteService(t *testing.T) *services.Pagination {
+	baseParams := types.ListParams{
+		Table: "users u",
+		ColumnDefs: []types.ColumnDef{
+			types.NewColumnDef("id", "u.id", types.ColumnTypeInt),
+			types.NewColumnDef("name", "u.name", types.ColumnTypeText),
+			types.NewColumnDef("email", "u.email", types.ColumnTypeText),
+			types.NewColumnDef("score", "u.score", types.ColumnTypeInt),
+			types.NewColumnDef("minScore", "u.min_score", types.ColumnTypeInt),
+			types.NewColumnDef("created", "u.created_at", types.ColumnTypeTimestamp),
+		},
+		Subqueries: types.Subqueries{
+			"has_orders": "EXISTS (SELECT 1 FROM orders o WHERE o.user_id = u.id AND o.status = :val)",
+		},
+		CursorKey:    "id",
+		CursorSecret: []byte("secret"),
+	}
+
+	return services.PaginationService(setupSQLiteDB(t), baseParams)
+}
+
+func getIds(items []map[string]any) []any {
+	ids := []any{}
+	for _, item := range items {
+		ids = append(ids, item["id"])
+	}
+	return ids
+}
+
+func TestSQLite_Filters(t *testing.T) {
+	paginationService := newSQLiteService(t)
+
+	cases := []struct {
+		name    string
+		filters []request.FilterRequest
+		ids     []any
+	}{
+		{"simple", []request.FilterRequest{{Attr: "name", Val: "Jane"}}, []any{int64(2)}},
+		{"numeric", []request.FilterRequest{{Type: "NUMERIC", Attr: "score", Opr: ">=", Val: "20"}}, []any{int64(2), int64(3)}},
+		{"column", []request.FilterRequest{{Type: "COLUMN", Attr: "score", Opr: "<", Val: "minScore"}}, []any{int64(2)}},
+		{"between", []request.FilterRequest{{Type: "BETWEEN", Attr: "score", Vals: []string{"10", "20"}}}, []any{int64(1), int64(2)}},
+		{"not between", []request.FilterRequest{{Type: "NOT_BETWEEN", Attr: "score", Vals: []string{"10", "20"}}}, []any{int64(3)}},
+		{"in", []request.FilterRequest{{Type: "IN", Attr: "id", Vals: []string{"1", "3"}}}, []any{int64(1), int64(3)}},
+		{"not in", []request.FilterRequest{{Type: "NOT_IN", Attr: "name", Vals: []string{"John", "Bob"}}}, []any{int64(2), int64(4)}},
+		{"empty in", []request.FilterRequest{{Type: "IN", Attr: "id", Vals: []string{}}}, []any{}},
+		{"empty not in", []request.FilterRequest{{Type: "NOT_IN", Attr: "id"}}, []any{int64(1), int64(2), int64(3), int64(4)}},
+		{"null", []request.FilterRequest{{Type: "NULL", Attr: "email"}}, []any{int64(3)}},
+		{"not null", []request.FilterRequest{{Type: "NOT_NULL", Attr: "score"}}, []any{int64(1), int64(2), int64(3)}},
+		{"date", []request.FilterRequest{{Type: "DATE", Attr: "created", Val: "2024-01-15"}}, []any{int64(1)}},
+		{"date between", []request.FilterRequest{{Type: "DATE_BETWEEN", Attr: "created", Vals: []string{"2024-01-16", "2024-02-01"}}}, []any{int64(2), int64(3)}},
+		{"term ilike", []request.FilterRequest{{Type: "TERM", Attrs: []string{"name", "email"}, Opr: "ILIKE", Val: "%john%"}}, []any{int64(1)}},
+		{"sub", []request.FilterRequest{{Type: "SUB", Attr: "has_orders", Val: "OPEN"}}, []any{int64(1), int64(3)}},
+		{"group", []request.FilterRequest{
+			{Attr: "score", Opr: ">", Val: "5"},
+			{Type: "GROUP", Filters: []request.FilterRequest{
+				{Attr: "name", Val: "John"},
+				{Attr: "name", Val: "Bob", Conn: "OR"},
+			}},
+		}, []any{int64(1), int64(3)}},
+		// La negación conserva las filas donde la condición es desconocida (score NULL)
+		{"not group", []request.FilterRequest{
+			{Type: "NOT_GROUP", Filters: []request.FilterRequest{{Type: "NUMERIC", Attr: "score", Opr: ">", Val: "15"}}},
+		}, []any{int64(1), int64(4)}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			items, err := paginationService.FindAll(c.filters, request.FindRequest{Order: types.Order{{Column: "id", Direction: "asc"}}}, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, c.ids, getIds(items))
+		})
+	}
+}
+
+func TestSQLite_RSQLFilters(t *testing.T) {
+	paginationService := newSQLiteService(t)
+	columns := types.Columns{"id": "u.id", "name": "u.name", "score": "u.score", "created": "u.created_at"}
+
+	cases := []struct {
+		expression string
+		ids        []any
+	}{
+		{"name==John,name==Bob;score=gt=20", []any{int64(1), int64(3)}},
+		{"(name==John,name==Bob);score=gt=20", []any{int64(3)}},
+		{"name==J*;created=between=(2024-01-16,2024-12-31)", []any{int64(2)}},
+		{"score=null=true or id=in=(1,2)", []any{int64(1), int64(2), int64(4)}},
+		// "_" se compara literalmente, sin escapar coincidiría con John y Jane
+		{"name==J_*", []any{}},
+		{"name=ilike=*O*", []any{int64(1), int64(3)}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.expression, func(t *testing.T) {
+			filters, err := request.ParseRSQL(c.expression, columns)
+			assert.NoError(t, err)
+
+			items, err := paginationService.FindAll(filters, request.FindRequest{Order: types.Order{{Column: "id", Direction: "asc"}}}, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, c.ids, getIds(items))
+		})
+	}
+}
+
+func TestSQLite_Pagination(t *testing.T) {
+	paginationService := newSQLiteService(t)
+
+	paginated, err := paginationService.FindPaginated(
+		[]request.FilterRequest{{Type: "NOT_NULL", Attr: "email"}},
+		request.PaginationRequest{Page: 2, Limit: 2, Count: true, Order: types.Order{{Column: "score", Direction: "desc nulls first"}}},
+		nil,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, paginated.TotalItems)
+	assert.Equal(t, 2, paginated.TotalPages)
+	assert.Equal(t, []any{int64(1)}, getIds(paginated.Items))
+
+	offset, err := paginationService.FindPaginatedOffset(
+		[]request.FilterRequest{{Attr: "score", Opr: ">", Val: "10"}},
+		request.PaginationOffsetRequest{Offset: 1, Limit: 5, Order: types.Order{{Column: "id", Direction: "asc"}}},
+		nil,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, offset.TotalItems)
+	assert.Equal(t, 2, offset.FilteredItems)
+	assert.Equal(t, []any{int64(3)}, getIds(offset.Items))
+
+	select2, err := paginationService.FindSelect2(
+		[]request.FilterRequest{{Attr: "name", Opr: "LIKE", Val: "J%"}},
+		request.InfiniteScrollRequest{Order: types.Order{{Column: "name", Direction: "asc"}}},
+		"id", "name",
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]any{{"value": int64(2), "label": "Jane"}, {"value": int64(1), "label": "John"}}, select2.Items)
+}
+
+func TestSQLite_ExcludeAllColumns(t *testing.T) {
+	paginationService := newSQLiteService(t)
+	exclusions := []string{"id", "name", "email", "score", "minScore", "created"}
+
+	// Excluir todas las columnas no vuelve a seleccionarlas
+	_, err := paginationService.FindAll([]request.FilterRequest{}, request.FindRequest{}, &exclusions)
+	assert.ErrorIs(t, err, services.ErrNoSelectedColumns)
+
+	_, err = paginationService.FindPaginated([]request.FilterRequest{}, request.PaginationRequest{Limit: 1}, &exclusions)
+	assert.ErrorIs(t, err, services.ErrNoSelectedColumns)
+
+	_, err = paginationService.Stream([]request.FilterRequest{}, request.FindRequest{}, &exclusions)
+	assert.ErrorIs(t, err, services.ErrNoSelectedColumns)
+}
+
+func TestSQLite_Aggregates(t *testing.T) {
+	paginationService := newSQLiteService(t)
+
+	paginated, err := paginationService.FindPaginated(
+		[]request.FilterRequest{{Type: "NOT_NULL", Attr: "score"}},
+		request.PaginationRequest{Limit: 1, Aggregates: map[string]string{"score": "sum", "minScore": "avg", "id": "count"}},
+		nil,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(paginated.Items))
+	assert.Equal(t, map[string]any{"id": int64(3), "minScore": 40.0 / 3, "score": int64(60)}, paginated.Aggregates)
+}
+
+func TestSQLite_Facets(t *testing.T) {
+	paginationService := newSQLiteService(t)
+
+	filters := []request.FilterRequest{
+		{Type: "IN", Attr: "name", Vals: []string{"John", "Bob"}},
+		{Type: "NOT_NULL", Attr: "email"},
+	}
+
+	facets, err := paginationService.FindFacets(filters, request.FacetRequest{Columns: []string{"name", "email"}, Disjunctive: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []response.FacetValue{
+		{Value: "Alice", Count: 1},
+		{Value: "Jane", Count: 1},
+		{Value: "John", Count: 1},
+	}, facets.Facets["name"])
+	assert.Equal(t, []response.FacetValue{
+		{Value: nil, Count: 1},
+		{Value: "JOHN@MAIL.COM", Count: 1},
+	}, facets.Facets["email"])
+}
+
+func TestSQLite_Stream(t *testing.T) {
+	paginationService := newSQLiteService(t)
+	order := types.Order{{Column: "id", Direction: "desc"}}
+
+	ids := []any{}
+	err := paginationService.Iterate([]request.FilterRequest{{Type: "NOT_NULL", Attr: "email"}}, request.FindRequest{Order: order}, nil, func(row map[string]any) error {
+		ids = append(ids, row["id"])
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []any{int64(4), int64(2), int64(1)}, ids)
+
+	// Con una sola conexión, la siguiente consulta solo funciona si el corte liberó el cursor
+	stream, err := paginationService.Stream([]request.FilterRequest{}, request.FindRequest{Order: order}, nil)
+	assert.NoError(t, err)
+	assert.True(t, stream.Next())
+	assert.Equal(t, int64(4), stream.Row()["id"])
+	assert.NoError(t, stream.Close())
+
+	count, err := paginationService.Count([]request.FilterRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count)
+}
+
+func TestSQLite_Cursor(t *testing.T) {
+	paginationService := newSQLiteService(t)
+
+	order := types.Order{{Column: "score", Direction: "desc"}}
+	filters := []request.FilterRequest{{Type: "NOT_NULL", Attr: "score"}}
+
+	first, err := paginationService.FindCursor(filters, request.CursorRequest{Limit: 2, Order: order}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []any{int64(3), int64(2)}, getIds(first.Items))
+
+	second, err := paginationService.FindCursor(filters, request.CursorRequest{Limit: 2, Order: order, Cursor: first.NextCursor}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []any{int64(1)}, getIds(second.Items))
+	assert.Empty(t, second.NextCursor)
+
+	previous, err := paginationService.FindCursor(filters, request.CursorRequest{Limit: 2, Order: order, Cursor: second.PrevCursor}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []any{int64(3), int64(2)}, getIds(previous.Items))
+}
+
+func TestSQLite_CursorNullSortKey(t *testing.T) {
+	paginationService := newSQLiteService(t)
+
+	// Alice no tiene score: SQLite ubica los NULL primero en ASC y al final en DESC
+	cases := []struct {
+		direction string
+		ids       []any
+	}{
+		{"asc", []any{int64(4), int64(1), int64(2), int64(3)}},
+		{"desc", []any{int64(3), int64(2), int64(1), int64(4)}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.direction, func(t *testing.T) {
+			order := types.Order{{Column: "score", Direction: c.direction}}
+
+			// Avanzamos de a una fila hasta el final
+			ids := []any{}
+			var prevCursors []string
+			cursor := ""
+			for {
+				page, err := paginationService.FindCursor([]request.FilterRequest{}, request.CursorRequest{Limit: 1, Order: order, Cursor: cursor}, nil)
+				require.NoError(t, err)
+				ids = append(ids, getIds(page.Items)...)
+				prevCursors = append(prevCursors, page.PrevCursor)
+				if page.NextCursor == "" {
+					break
+				}
+				cursor = page.NextCursor
+			}
+			assert.Equal(t, c.ids, ids)
+
+			// Retrocedemos desde la última página
+			back := []any{}
+			cursor = prevCursors[len(prevCursors)-1]
+			for cursor != "" {
+				page, err := paginationService.FindCursor([]request.FilterRequest{}, request.CursorRequest{Limit: 1, Order: order, Cursor: cursor}, nil)
+				require.NoError(t, err)
+				back = append(getIds(page.Items), back...)
+				cursor = page.PrevCursor
+			}
+			assert.Equal(t, c.ids[:len(c.ids)-1], back)
+		})
+	}
+}
+
+func TestSQLite_CursorWithOrFilters(t *testing.T) {
+	paginationService := newSQLiteService(t)
+
+	order := types.Order{{Column: "name", Direction: "asc"}}
+	filters := []request.FilterRequest{{Attr: "name", Val: "John"}, {Attr: "name", Val: "Jane", Conn: "OR"}}
+
+	// El predicado del cursor se aplica a todas las filas filtradas, no solo a la última alternativa
+	ids := []any{}
+	cursor := ""
+	for page := 0; page < 5; page++ {
+		result, err := paginationService.FindCursor(filters, request.CursorRequest{Limit: 1, Order: order, Cursor: cursor}, nil)
+		require.NoError(t, err)
+		ids = append(ids, getIds(result.Items)...)
+		if result.NextCursor == "" {
+			break
+		}
+		cursor = result.NextCursor
+	}
+	assert.Equal(t, []any{int64(2), int64(1)}, ids)
+}
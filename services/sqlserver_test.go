@@ -0,0 +1,131 @@
+package services_test
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/services"
+	"github.com/devsstudio/gosql/types"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// sqlserverDialector imita al driver de SQL Server de GORM: se llama "sqlserver" y enlaza "@pN"
+type sqlserverDialector struct {
+	gorm.Dialector
+}
+
+func (sqlserverDialector) Name() string {
+	return "sqlserver"
+}
+
+func (sqlserverDialector) BindVarTo(writer clause.Writer, stmt *gorm.Statement, v interface{}) {
+	writer.WriteString("@p" + strconv.Itoa(len(stmt.Vars)))
+}
+
+func setupSQLServerMockDB() (*gorm.DB, sqlmock.Sqlmock, error) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gormDB, err := gorm.Open(sqlserverDialector{
+		Dialector: mysql.New(mysql.Config{Conn: db, SkipInitializeWithVersion: true}),
+	}, &gorm.Config{})
+
+	return gormDB, mock, err
+}
+
+func TestPaginationService_SQLServer(t *testing.T) {
+	db, mock, err := setupSQLServerMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	where := "tenant_id = :tenant_id"
+	baseParams := types.ListParams{
+		Table:        "users",
+		Columns:      types.Columns{"id": "id", "name": "name", "created": "created_at"},
+		Where:        &where,
+		Placeholders: map[string]any{"tenant_id": 7},
+	}
+	paginationService := services.PaginationService(db, baseParams)
+
+	filters := []request.FilterRequest{
+		{Type: "TERM", Attrs: []string{"name"}, Opr: "ILIKE", Val: "%jo%"},
+		{Type: "DATE", Attr: "created", Val: "2024-01-01"},
+		{Type: "DATE_BETWEEN", Attr: "created", Vals: []string{"2024-01-01", "2024-01-31"}},
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT created_at as created, id as id, name as name, COUNT(*) OVER() as gosql_total_items FROM users WHERE (tenant_id = @p1) AND ((name COLLATE Latin1_General_CI_AS LIKE @p2 ESCAPE '\\') AND (created_at BETWEEN CAST(@p3 AS DATETIME2) AND DATEADD(day, 1, CAST(@p4 AS DATETIME2))) AND (created_at BETWEEN CAST(@p5 AS DATETIME2) AND DATEADD(day, 1, CAST(@p6 AS DATETIME2))))  ORDER BY name ASC OFFSET 10 ROWS FETCH NEXT 10 ROWS ONLY")).
+		WithArgs(7, "%jo%", "2024-01-01", "2024-01-01", "2024-01-01", "2024-01-31").
+		WillReturnRows(sqlmock.NewRows([]string{"created", "id", "name", "gosql_total_items"}).AddRow("2024-01-02", 11, "Jon", 11))
+
+	paginated, err := paginationService.FindPaginated(filters, request.PaginationRequest{
+		Page:  2,
+		Limit: 10,
+		Order: types.Order{{Column: "name", Direction: "asc"}},
+		Count: true,
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 11, paginated.TotalItems)
+	assert.Equal(t, 1, len(paginated.Items))
+
+	// Sin orden se agrega el ORDER BY obligatorio para OFFSET/FETCH
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT created_at as created, id as id, name as name, COUNT(*) OVER() as gosql_total_items FROM users WHERE tenant_id = @p1  ORDER BY (SELECT NULL) OFFSET 20 ROWS FETCH NEXT 5 ROWS ONLY")).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"created", "id", "name", "gosql_total_items"}).AddRow("2024-01-02", 21, "Ana", 30))
+
+	_, err = paginationService.FindPaginatedOffset([]request.FilterRequest{}, request.PaginationOffsetRequest{Offset: 20, Limit: 5}, nil)
+	assert.NoError(t, err)
+
+	// NULLS FIRST se emula con CASE
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT created_at as created, id as id, name as name FROM users WHERE tenant_id = @p1  ORDER BY CASE WHEN created_at IS NULL THEN 0 ELSE 1 END, created_at DESC OFFSET 0 ROWS FETCH NEXT 3 ROWS ONLY")).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"created", "id", "name"}))
+
+	_, err = paginationService.FindAll([]request.FilterRequest{}, request.FindRequest{
+		Limit: 3,
+		Order: types.Order{{Column: "created", Direction: "desc nulls first"}},
+	}, nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPaginationService_SQLServerCursor(t *testing.T) {
+	db, mock, err := setupSQLServerMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	baseParams := types.ListParams{
+		Table:        "users",
+		Columns:      types.Columns{"id": "id", "name": "name"},
+		CursorKey:    "id",
+		CursorSecret: []byte("secret"),
+	}
+	paginationService := services.PaginationService(db, baseParams)
+	order := types.Order{{Column: "name", Direction: "asc"}}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id as id, name as name FROM users WHERE 1 = 1  ORDER BY name ASC, id ASC OFFSET 0 ROWS FETCH NEXT 2 ROWS ONLY")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "A").AddRow(2, "B"))
+
+	first, err := paginationService.FindCursor([]request.FilterRequest{}, request.CursorRequest{Limit: 1, Order: order}, nil)
+	assert.NoError(t, err)
+
+	// SQL Server no compara filas, el predicado se expande
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id as id, name as name FROM users WHERE (1 = 1) AND ((name > @p1) OR (name = @p2 AND id > @p3))  ORDER BY name ASC, id ASC OFFSET 0 ROWS FETCH NEXT 2 ROWS ONLY")).
+		WithArgs("A", "A", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "B"))
+
+	_, err = paginationService.FindCursor([]request.FilterRequest{}, request.CursorRequest{Cursor: first.NextCursor, Limit: 1, Order: order}, nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
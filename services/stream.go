@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/types"
+	"gorm.io/gorm"
+)
+
+// RowStream recorre el resultado de una consulta fila por fila sin cargarlo en memoria.
+// Debe cerrarse con Close; Next lo cierra solo al llegar al final o ante un error.
+type RowStream struct {
+	cols       []string
+	converters []types.ValueConverter
+	rows       *sql.Rows
+	row        map[string]any
+	err        error
+	tx         *gorm.DB // Transacción abierta cuando el dialecto fija el tiempo máximo con una sentencia
+	cancel     context.CancelFunc
+	closed     bool
+}
+
+// Stream ejecuta la misma consulta que FindAll pero devuelve las filas de a una.
+// El tiempo máximo del servicio aplica a todo el recorrido, para exportaciones largas
+// conviene usar WithTimeout(0).
+func (service *Pagination) Stream(filters []request.FilterRequest, findRequest request.FindRequest, exclusions *[]string) (*RowStream, error) {
+	query, err := service.newQuery(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	query.limit, query.offset = getLimit(findRequest), findRequest.Offset
+	query.order, err = service.getOrder(findRequest.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	cols, selectPairs, err := service.getSelectCols(exclusions, findRequest.Include)
+	if err != nil {
+		return nil, err
+	}
+	return query.openStream(query.getSql(selectPairs), cols)
+}
+
+// Iterate llama a fn por cada fila. Si fn devuelve un error se detiene el recorrido,
+// se cierra el cursor y se devuelve ese error.
+func (service *Pagination) Iterate(filters []request.FilterRequest, findRequest request.FindRequest, exclusions *[]string, fn func(row map[string]any) error) error {
+	stream, err := service.Stream(filters, findRequest, exclusions)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	for stream.Next() {
+		if err := fn(stream.Row()); err != nil {
+			return err
+		}
+	}
+	return stream.Err()
+}
+
+// openStream abre el cursor aplicando el tiempo máximo igual que execute, pero manteniendo
+// el contexto y la transacción abiertos hasta que se cierre el stream.
+func (query *query) openStream(sql string, cols []string) (*RowStream, error) {
+	stream := &RowStream{cols: cols}
+
+	db := query.service.db
+	if query.service.timeout > 0 {
+		ctx, cancel := context.WithTimeout(db.Statement.Context, query.service.timeout)
+		stream.cancel = cancel
+		db = db.WithContext(ctx)
+
+		if statement := query.service.dialect.TimeoutStatement(query.service.timeout); statement != "" {
+			tx := db.Begin()
+			if tx.Error != nil {
+				stream.Close()
+				return nil, newQueryError(tx.Error)
+			}
+			stream.tx = tx
+			if err := tx.Exec(statement).Error; err != nil {
+				stream.Close()
+				return nil, newQueryError(err)
+			}
+			db = tx
+		}
+	}
+
+	rows, err := db.Raw(sql, query.getArgs()...).Rows()
+	if err != nil {
+		stream.Close()
+		return nil, newQueryError(err)
+	}
+	stream.rows = rows
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		stream.Close()
+		return nil, newQueryError(err)
+	}
+	stream.converters = query.service.getConverters(cols, columnTypes)
+
+	return stream, nil
+}
+
+// Columns devuelve los alias de las columnas de cada fila
+func (stream *RowStream) Columns() []string {
+	return stream.cols
+}
+
+// Next avanza a la siguiente fila, devuelve false al terminar o ante un error
+func (stream *RowStream) Next() bool {
+	if stream.closed {
+		return false
+	}
+
+	if !stream.rows.Next() {
+		stream.err = newQueryError(stream.rows.Err())
+		stream.Close()
+		return false
+	}
+
+	row, err := scanRow(stream.rows, stream.cols)
+	if err == nil {
+		err = normalizeRow(row, stream.cols, stream.converters)
+	}
+	if err != nil {
+		stream.err = newQueryError(err)
+		stream.Close()
+		return false
+	}
+	stream.row = row
+	return true
+}
+
+// Row devuelve la fila actual. Cada llamada a Next crea un mapa nuevo, puede conservarse.
+func (stream *RowStream) Row() map[string]any {
+	return stream.row
+}
+
+// Err devuelve el error que detuvo el recorrido, si lo hubo
+func (stream *RowStream) Err() error {
+	return stream.err
+}
+
+// Close libera el cursor, la transacción y el contexto. Puede llamarse más de una vez.
+func (stream *RowStream) Close() error {
+	if stream.closed {
+		return nil
+	}
+	stream.closed = true
+
+	var err error
+	if stream.rows != nil {
+		err = stream.rows.Close()
+	}
+	// La transacción solo fija el tiempo máximo, no hay cambios que confirmar
+	if stream.tx != nil {
+		stream.tx.Rollback()
+	}
+	if stream.cancel != nil {
+		stream.cancel()
+	}
+	return err
+}
+
+// All devuelve las filas con la forma de iter.Seq2, para recorrerlas con range desde Go 1.23.
+// Cortar el recorrido cierra el cursor.
+func (stream *RowStream) All() func(yield func(map[string]any, error) bool) {
+	return func(yield func(map[string]any, error) bool) {
+		defer stream.Close()
+		for stream.Next() {
+			if !yield(stream.Row(), nil) {
+				return
+			}
+		}
+		if err := stream.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package services_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/services"
+	"github.com/devsstudio/gosql/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaginationService_Iterate(t *testing.T) {
+	db, mock, err := setupMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	baseParams := types.ListParams{
+		Table:      "users",
+		ColumnDefs: []types.ColumnDef{types.NewColumnDef("id", "id", types.ColumnTypeInt)},
+	}
+	paginationService := services.PaginationService(db, baseParams)
+	order := types.Order{{Column: "id", Direction: "asc"}}
+
+	// Recorrido completo
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id as id FROM users WHERE 1 = 1  ORDER BY id ASC")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2).AddRow(3)).
+		RowsWillBeClosed()
+
+	ids := []any{}
+	err = paginationService.Iterate([]request.FilterRequest{}, request.FindRequest{Order: order}, nil, func(row map[string]any) error {
+		ids = append(ids, row["id"])
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []any{int64(1), int64(2), int64(3)}, ids)
+
+	// Corte anticipado, el cursor se cierra y se devuelve el error del callback
+	stop := errors.New("stop")
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id as id FROM users WHERE 1 = 1  ORDER BY id ASC")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2).AddRow(3)).
+		RowsWillBeClosed()
+
+	count := 0
+	err = paginationService.Iterate([]request.FilterRequest{}, request.FindRequest{Order: order}, nil, func(row map[string]any) error {
+		count++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, count)
+
+	// Error del driver durante el recorrido
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id as id FROM users WHERE 1 = 1  ORDER BY id ASC")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2).RowError(1, errors.New("broken"))).
+		RowsWillBeClosed()
+
+	stream, err := paginationService.Stream([]request.FilterRequest{}, request.FindRequest{Order: order}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id"}, stream.Columns())
+
+	var rows []map[string]any
+	var streamErr error
+	stream.All()(func(row map[string]any, err error) bool {
+		if err != nil {
+			streamErr = err
+			return false
+		}
+		rows = append(rows, row)
+		return true
+	})
+	assert.EqualError(t, streamErr, "broken")
+	assert.Equal(t, 1, len(rows))
+	assert.NoError(t, stream.Close())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPaginationService_StreamTimeoutPostgres(t *testing.T) {
+	db, mock, err := setupPostgresMockDB()
+	assert.NoError(t, err)
+
+	defer mock.ExpectClose()
+
+	baseParams := types.ListParams{
+		Table:   "users",
+		Columns: types.Columns{"id": "id"},
+		Timeout: 2 * time.Second,
+	}
+	paginationService := services.PaginationService(db, baseParams)
+
+	// La transacción que fija statement_timeout queda abierta hasta cerrar el stream
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("SET LOCAL statement_timeout = 2000")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id as id FROM users WHERE 1 = 1")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2)).
+		RowsWillBeClosed()
+	mock.ExpectRollback()
+
+	stream, err := paginationService.Stream([]request.FilterRequest{}, request.FindRequest{}, nil)
+	assert.NoError(t, err)
+
+	stream.All()(func(row map[string]any, err error) bool {
+		return false
+	})
+	assert.False(t, stream.Next())
+	assert.NoError(t, stream.Err())
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devsstudio/gosql/types"
+)
+
+var (
+	decimalRegexp = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)$`)
+	uuidRegexp    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+	// Formatos aceptados para fechas con hora, del más al menos específico
+	timestampLayouts = []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05.999999999",
+		"2006-01-02 15:04:05",
+		"2006-01-02 15:04",
+		"2006-01-02",
+	}
+)
+
+// getFilterValue convierte el valor de un filtro al tipo declarado de la columna.
+// Las comparaciones LIKE/ILIKE y las columnas sin tipo reciben el texto tal cual.
+func (service *Pagination) getFilterValue(attr string, opr string, raw string) (any, error) {
+	if opr == "LIKE" || opr == "ILIKE" {
+		return raw, nil
+	}
+
+	def := service.getColumnDef(attr)
+	if def == nil || def.Type == "" {
+		return raw, nil
+	}
+	columnType := def.Type
+
+	value, err := parseValue(columnType, raw)
+	if err != nil {
+		return nil, &ValueError{Attr: attr, Value: raw, Type: columnType}
+	}
+	return value, nil
+}
+
+// getDateFilterValue convierte el valor de un filtro DATE o DATE_BETWEEN, que siempre representa un día
+func (service *Pagination) getDateFilterValue(attr string, raw string) (any, error) {
+	var columnType types.ColumnType
+	if def := service.getColumnDef(attr); def != nil {
+		columnType = def.Type
+	}
+	if columnType != types.ColumnTypeDate && columnType != types.ColumnTypeTimestamp {
+		return raw, nil
+	}
+
+	value, err := parseValue(types.ColumnTypeDate, raw)
+	if err != nil {
+		return nil, &ValueError{Attr: attr, Value: raw, Type: types.ColumnTypeDate}
+	}
+	return value, nil
+}
+
+func parseValue(columnType types.ColumnType, raw string) (any, error) {
+	trimmed := strings.TrimSpace(raw)
+
+	switch columnType {
+	case types.ColumnTypeInt:
+		return strconv.ParseInt(trimmed, 10, 64)
+	case types.ColumnTypeFloat:
+		return strconv.ParseFloat(trimmed, 64)
+	case types.ColumnTypeDecimal:
+		// Se envía como texto para no perder precisión
+		if !decimalRegexp.MatchString(trimmed) {
+			return nil, errors.New("invalid decimal")
+		}
+		return trimmed, nil
+	case types.ColumnTypeBool:
+		return strconv.ParseBool(trimmed)
+	case types.ColumnTypeUUID:
+		if !uuidRegexp.MatchString(trimmed) {
+			return nil, errors.New("invalid uuid")
+		}
+		return strings.ToLower(trimmed), nil
+	case types.ColumnTypeDate:
+		return parseTime(trimmed, true)
+	case types.ColumnTypeTimestamp:
+		return parseTime(trimmed, false)
+	case types.ColumnTypeJSON:
+		if !json.Valid([]byte(trimmed)) {
+			return nil, errors.New("invalid json")
+		}
+		return trimmed, nil
+	default:
+		return raw, nil
+	}
+}
+
+// parseTime interpreta una fecha con hora; si dateOnly es verdadero se descarta la hora
+func parseTime(value string, dateOnly bool) (time.Time, error) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			if dateOnly {
+				return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("invalid time")
+}
+
+// toArray convierte los valores de un IN en un slice tipado para enlazarlo como arreglo.
+// Si los tipos no coinciden se envían como texto.
+func toArray(values []any) any {
+	switch values[0].(type) {
+	case int64:
+		if array, ok := toTypedArray[int64](values); ok {
+			return array
+		}
+	case float64:
+		if array, ok := toTypedArray[float64](values); ok {
+			return array
+		}
+	case bool:
+		if array, ok := toTypedArray[bool](values); ok {
+			return array
+		}
+	case time.Time:
+		if array, ok := toTypedArray[time.Time](values); ok {
+			return array
+		}
+	}
+
+	array := make([]string, len(values))
+	for i, value := range values {
+		array[i] = fmt.Sprint(value)
+	}
+	return array
+}
+
+func toTypedArray[T any](values []any) ([]T, bool) {
+	array := make([]T, len(values))
+	for i, value := range values {
+		typed, ok := value.(T)
+		if !ok {
+			return nil, false
+		}
+		array[i] = typed
+	}
+	return array, true
+}
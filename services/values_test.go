@@ -0,0 +1,102 @@
+package services_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/devsstudio/gosql/request"
+	"github.com/devsstudio/gosql/services"
+	"github.com/devsstudio/gosql/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTypedService(t *testing.T) (*services.Pagination, sqlmock.Sqlmock) {
+	db, mock, err := setupMockDB()
+	assert.NoError(t, err)
+
+	baseParams := types.ListParams{
+		Table: "orders",
+		Columns: types.Columns{
+			"id":      "o.id",
+			"active":  "o.active",
+			"amount":  "o.amount",
+			"rate":    "o.rate",
+			"ref":     "o.ref",
+			"created": "o.created_at",
+			"day":     "o.day",
+			"meta":    "o.meta",
+			"note":    "o.note",
+		},
+		ColumnTypes: types.ColumnTypes{
+			"id":      types.ColumnTypeInt,
+			"active":  types.ColumnTypeBool,
+			"amount":  types.ColumnTypeDecimal,
+			"rate":    types.ColumnTypeFloat,
+			"ref":     types.ColumnTypeUUID,
+			"created": types.ColumnTypeTimestamp,
+			"day":     types.ColumnTypeDate,
+			"meta":    types.ColumnTypeJSON,
+			"note":    types.ColumnTypeText,
+		},
+	}
+
+	return services.PaginationService(db, baseParams), mock
+}
+
+func TestPaginationService_TypedValues(t *testing.T) {
+	paginationService, mock := newTypedService(t)
+
+	defer mock.ExpectClose()
+
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	created := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM orders WHERE (1 = 1) AND ((o.id = ?) AND (o.active = ?) AND (o.amount >= ?) AND (o.rate < ?) AND (o.ref = ?) AND (o.created_at > ?) AND (o.note LIKE ?) AND (o.id BETWEEN ? AND ?) AND (o.day BETWEEN ? AND DATE_ADD(?, INTERVAL 1 DAY)))")).
+		WithArgs(int64(42), true, "10.50", 0.25, "0f8fad5b-d9cb-469f-a165-70867728950e", created, "%abc%", int64(1), int64(9), day, day).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	filters := []request.FilterRequest{
+		{Attr: "id", Val: " 42 "},
+		{Attr: "active", Val: "true"},
+		{Attr: "amount", Opr: ">=", Val: "10.50"},
+		{Attr: "rate", Opr: "<", Val: "0.25"},
+		{Attr: "ref", Val: "0F8FAD5B-D9CB-469F-A165-70867728950E"},
+		{Attr: "created", Opr: ">", Val: "2024-01-15T10:30:00Z"},
+		{Attr: "note", Opr: "LIKE", Val: "%abc%"},
+		{Type: "BETWEEN", Attr: "id", Vals: []string{"1", "9"}},
+		{Type: "DATE", Attr: "day", Val: "2024-01-15"},
+	}
+	count, err := paginationService.Count(filters)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPaginationService_TypedValuesErrors(t *testing.T) {
+	paginationService, _ := newTypedService(t)
+
+	cases := []struct {
+		filter  request.FilterRequest
+		message string
+	}{
+		{request.FilterRequest{Attr: "id", Val: "1.5"}, "val '1.5' is not a valid int for attribute 'id'"},
+		{request.FilterRequest{Attr: "active", Val: "yes"}, "val 'yes' is not a valid bool for attribute 'active'"},
+		{request.FilterRequest{Attr: "amount", Val: "1e3"}, "val '1e3' is not a valid decimal for attribute 'amount'"},
+		{request.FilterRequest{Attr: "ref", Val: "not-a-uuid"}, "val 'not-a-uuid' is not a valid uuid for attribute 'ref'"},
+		{request.FilterRequest{Attr: "created", Val: "yesterday"}, "val 'yesterday' is not a valid timestamp for attribute 'created'"},
+		{request.FilterRequest{Attr: "meta", Val: "{oops"}, "val '{oops' is not a valid json for attribute 'meta'"},
+		{request.FilterRequest{Type: "DATE_BETWEEN", Attr: "day", Vals: []string{"2024-01-01", "2024-13-01"}}, "val '2024-13-01' is not a valid date for attribute 'day'"},
+	}
+
+	for _, c := range cases {
+		_, err := paginationService.Count([]request.FilterRequest{c.filter})
+		assert.EqualError(t, err, c.message)
+
+		var valueErr *services.ValueError
+		assert.True(t, errors.As(err, &valueErr))
+	}
+}
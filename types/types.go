@@ -1,15 +1,199 @@
 package types
 
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+)
+
 type Columns map[string]string
 
-type Order map[string]string
+// ColumnType es el tipo de dato de una columna, define cómo se interpretan los valores de los filtros
+type ColumnType string
+
+const (
+	ColumnTypeInt       ColumnType = "int"
+	ColumnTypeFloat     ColumnType = "float"
+	ColumnTypeDecimal   ColumnType = "decimal"
+	ColumnTypeBool      ColumnType = "bool"
+	ColumnTypeUUID      ColumnType = "uuid"
+	ColumnTypeDate      ColumnType = "date"
+	ColumnTypeTimestamp ColumnType = "timestamp"
+	ColumnTypeJSON      ColumnType = "json"
+	ColumnTypeText      ColumnType = "text"
+)
+
+// ColumnDef es la definición completa de una columna del listado
+type ColumnDef struct {
+	Name        string         // Alias con el que se expone la columna
+	Expression  string         // Expresión SQL de la columna
+	Label       string         // Encabezado en las exportaciones, vacío usa Name
+	Type        ColumnType     // Tipo de dato, vacío se trata como texto
+	Filterable  bool           // Puede usarse en filtros y en comparaciones COLUMN
+	Sortable    bool           // Puede usarse en el ORDER BY
+	Selectable  bool           // Puede devolverse en los resultados
+	Hidden      bool           // No se devuelve salvo que se incluya explícitamente
+	FilterTypes []string       // Tipos de filtro permitidos, vacío permite todos
+	Operators   []string       // Operadores permitidos, vacío permite todos
+	Converter   ValueConverter // Reemplaza la normalización del valor devuelto por el driver
+}
+
+// ValueConverter convierte el valor que devuelve el driver para una columna (puede ser nil)
+// en el valor que se entrega en los resultados.
+type ValueConverter func(value any) (any, error)
+
+// NewColumnDef crea una columna filtrable, ordenable y seleccionable, como las del mapa Columns
+func NewColumnDef(name string, expression string, columnType ColumnType) ColumnDef {
+	return ColumnDef{
+		Name:       name,
+		Expression: expression,
+		Type:       columnType,
+		Filterable: true,
+		Sortable:   true,
+		Selectable: true,
+	}
+}
+
+// ColumnTypes relaciona el alias de una columna con su tipo. Las columnas sin tipo
+// reciben los valores como texto.
+type ColumnTypes map[string]ColumnType
+
+// OrderBy es una clave de ordenamiento: alias de la columna y dirección
+// (ASC, DESC, opcionalmente seguida de NULLS FIRST o NULLS LAST).
+type OrderBy struct {
+	Column    string `json:"column"`
+	Direction string `json:"direction"`
+}
+
+// Order es la lista de claves de ordenamiento, se aplica en el orden recibido.
+// En JSON acepta un objeto {"a": "asc", "b": "desc"} respetando el orden de sus
+// claves, o un arreglo [{"column": "a", "direction": "asc"}].
+//
+// Antes Order era un map[string]string; el código que construía literales como
+// types.Order{"name": "asc"} debe migrar a types.Order{{Column: "name", Direction: "asc"}}
+// o usar OrderFromMap.
+type Order []OrderBy
+
+// OrderFromMap convierte un mapa alias => dirección en un Order. Como los mapas no
+// conservan el orden, las claves se ordenan alfabéticamente para que el resultado sea estable.
+func OrderFromMap(values map[string]string) Order {
+	if values == nil {
+		return nil
+	}
+
+	columns := make([]string, 0, len(values))
+	for column := range values {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	order := make(Order, 0, len(columns))
+	for _, column := range columns {
+		order = append(order, OrderBy{Column: column, Direction: values[column]})
+	}
+	return order
+}
 
 type Row map[string]interface{}
 
+// FieldError describe un parámetro inválido, Field es la ruta con los nombres JSON (p.e. "filters[0].opr")
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Subqueries relaciona un nombre con una condición SQL (p.e. un EXISTS) que puede
+// activarse con un filtro SUB. El valor del filtro se enlaza en ":val".
+type Subqueries map[string]string
+
 type ListParams struct {
 	Columns      Columns
+	ColumnTypes  ColumnTypes // Tipos de las columnas sin Type propio, el Type de ColumnDefs tiene prioridad
+	ColumnDefs   []ColumnDef // Definiciones completas, tienen prioridad sobre Columns
 	Table        string
 	Where        *string
 	Group        *string
 	Placeholders map[string]any
+	Subqueries   Subqueries
+	CursorKey    string                    // Alias de la columna única y NOT NULL usada como desempate en FindCursor
+	CursorSecret []byte                    // Clave con la que se firman los cursores de FindCursor
+	Timeout      time.Duration             // Tiempo máximo de ejecución de cada consulta, 0 sin límite
+	MaxInValues  int                       // Cantidad máxima de valores en filtros IN/NOT_IN, 0 usa el valor por defecto
+	Converters   map[string]ValueConverter // Conversores por alias para las columnas sin Converter propio
+}
+
+func (order Order) MarshalJSON() ([]byte, error) {
+	if order == nil {
+		return []byte("null"), nil
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for i, orderBy := range order {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+		column, err := json.Marshal(orderBy.Column)
+		if err != nil {
+			return nil, err
+		}
+		direction, err := json.Marshal(orderBy.Direction)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(column)
+		buffer.WriteByte(':')
+		buffer.Write(direction)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+func (order *Order) UnmarshalJSON(data []byte) error {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.Equal(trimmed, []byte("null")) {
+		*order = nil
+		return nil
+	}
+
+	// Forma de arreglo
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var items []OrderBy
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return err
+		}
+		*order = items
+		return nil
+	}
+
+	// Forma de objeto, se lee token por token para conservar el orden de las claves
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return errors.New("order should be an object or an array")
+	}
+
+	items := Order{}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		var direction string
+		if err := decoder.Decode(&direction); err != nil {
+			return err
+		}
+		items = append(items, OrderBy{Column: key.(string), Direction: direction})
+	}
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+
+	*order = items
+	return nil
 }